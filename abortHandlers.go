@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (cfg *appConfig) abortHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = cfg.abortMatch(currentGame, userId)
+	if errors.Is(err, errGameNotFound) {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// abortMatch aborts the game for one of its players and sends both of them
+// back to the lobby.
+func (cfg *appConfig) abortMatch(currentGame string, userId uuid.UUID) error {
+	msg, err := utils.TemplString(components.GameAborted())
+	if err != nil {
+		return err
+	}
+
+	var finished matches.Match
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = match.Abort(userId)
+		if err != nil {
+			return
+		}
+
+		match.Online.Hub.Broadcast(msg)
+		finished = match.Clone()
+	})
+
+	if !ok {
+		return fmt.Errorf("%w: %v", errGameNotFound, currentGame)
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg.finishOnlineMatch(currentGame, finished)
+
+	return nil
+}
+
+// watchFirstMove aborts the game once a player lets the time for their first
+// move run out. It checks again at every new deadline until both have moved.
+func (cfg *appConfig) watchFirstMove(currentGame string) {
+	var deadline time.Time
+	var waiting, aborted bool
+	var finished matches.Match
+
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.AbortIfFirstMoveMissed(time.Now()) {
+			msg, err := utils.TemplString(components.GameAborted())
+			if err != nil {
+				responses.LogError("couldn't render aborted game", err)
+			} else {
+				match.Online.Hub.Broadcast(msg)
+			}
+
+			finished = match.Clone()
+			aborted = true
+			return
+		}
+
+		deadline, waiting = match.FirstMoveDeadline()
+	})
+
+	if aborted {
+		cfg.finishOnlineMatch(currentGame, finished)
+		return
+	}
+
+	if waiting {
+		time.AfterFunc(time.Until(deadline), func() {
+			cfg.watchFirstMove(currentGame)
+		})
+	}
+}
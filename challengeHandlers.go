@@ -0,0 +1,293 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	layout "github.com/NikolaTosic-sudo/chess-live/containers/layouts"
+	"github.com/NikolaTosic-sudo/chess-live/internal/challenges"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+var errOpponentNotFound = errors.New("no player with that name")
+
+// challengesHandler lists the user's pending challenges, or moves them into
+// the game once someone accepted one of theirs.
+func (cfg *appConfig) challengesHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	if newGame, ok := cfg.Challenges.TakeStarted(userId, time.Now()); ok {
+		cfg.joinOnlineMatch(w, r, newGame, userId, false)
+		return
+	}
+
+	cfg.renderChallenges(w, r, userId)
+}
+
+func (cfg *appConfig) createChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.getUser(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	multiplier, err := cfg.getMultiplier(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't read the board size", err)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't decode request", err)
+		return
+	}
+
+	minutes, _ := strconv.Atoi(r.FormValue("minutes"))
+	addition, _ := strconv.Atoi(r.FormValue("addition"))
+	color := r.FormValue("color")
+
+	err = challenges.Validate(minutes, addition, color)
+	if err != nil {
+		cfg.challengeError(w, err)
+		return
+	}
+
+	challenge := challenges.Challenge{
+		From: components.OnlinePlayerStruct{
+			ID:         user.ID,
+			Name:       user.Name,
+			Image:      "/assets/images/user-icon.png",
+			Multiplier: multiplier,
+		},
+		FullTime: minutes * 60,
+		Addition: addition,
+		Color:    color,
+	}
+
+	opponent := strings.TrimSpace(r.FormValue("opponent"))
+	if opponent != "" {
+		users, err := cfg.database.GetUsersByName(r.Context(), opponent)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't find the player", err)
+			return
+		}
+		if len(users) != 1 {
+			cfg.challengeError(w, errOpponentNotFound)
+			return
+		}
+
+		challenge.To = users[0].ID
+		challenge.ToName = users[0].Name
+	}
+
+	_, err = cfg.Challenges.Create(challenge, time.Now())
+	if err != nil {
+		cfg.challengeError(w, err)
+		return
+	}
+
+	err = components.ChallengeError("").Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+		return
+	}
+
+	cfg.renderChallenges(w, r, user.ID)
+}
+
+// inviteHandler shows the page an invite link or a direct challenge opens.
+func (cfg *appConfig) inviteHandler(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+
+	challenge, ok := cfg.Challenges.Get(r.PathValue("id"), now)
+	if !ok {
+		err := layout.ChallengeInvite(components.ChallengeStruct{}, false, challenges.ErrNotFound.Error()).Render(r.Context(), w)
+		if err != nil {
+			responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
+		}
+		return
+	}
+
+	userId, err := cfg.getAccountId(r)
+	loggedIn := err == nil
+
+	var msg string
+	if loggedIn && challenge.From.ID == userId {
+		msg = "Send this link to your opponent"
+	} else if loggedIn && !challenge.IsInvite() && challenge.To != userId {
+		msg = challenges.ErrNotAllowed.Error()
+	}
+
+	err = layout.ChallengeInvite(challengeStruct(r, challenge, userId, now), loggedIn, msg).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
+	}
+}
+
+func (cfg *appConfig) acceptChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := cfg.getUser(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	multiplier, err := cfg.getMultiplier(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't read the board size", err)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	challenge, err := cfg.Challenges.Accept(id, user.ID, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	white, black := challenge.Seats(components.OnlinePlayerStruct{
+		ID:         user.ID,
+		Name:       user.Name,
+		Image:      "/assets/images/user-icon.png",
+		Multiplier: multiplier,
+	}, rand.IntN(2) == 0)
+
+	newGame, err := cfg.startOnlineMatch(white, black, challenge.FullTime, challenge.Addition, false)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't start the game", err)
+		return
+	}
+	cfg.Challenges.Started(id, newGame, time.Now())
+
+	cfg.joinOnlineMatch(w, r, newGame, user.ID, true)
+}
+
+func (cfg *appConfig) declineChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = cfg.Challenges.Decline(r.PathValue("id"), userId, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	if r.Header.Get("HX-Target") == "invite" {
+		_, err = fmt.Fprint(w, `<p class="text-2xl">Challenge declined</p><a href="/" class="block mt-4 text-gray-400 hover:text-white">Go to main page</a>`)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+		}
+		return
+	}
+
+	cfg.renderChallenges(w, r, userId)
+}
+
+func (cfg *appConfig) cancelChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = cfg.Challenges.Cancel(r.PathValue("id"), userId, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	cfg.renderChallenges(w, r, userId)
+}
+
+func (cfg *appConfig) renderChallenges(w http.ResponseWriter, r *http.Request, userId uuid.UUID) {
+	now := time.Now()
+	incoming, outgoing := cfg.Challenges.ForUser(userId, now)
+
+	var incomingList, outgoingList []components.ChallengeStruct
+	for _, c := range incoming {
+		incomingList = append(incomingList, challengeStruct(r, c, userId, now))
+	}
+	for _, c := range outgoing {
+		outgoingList = append(outgoingList, challengeStruct(r, c, userId, now))
+	}
+
+	err := components.ChallengeList(incomingList, outgoingList).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
+
+// challengeError keeps the challenge list as it is and shows why the
+// challenge couldn't be made.
+func (cfg *appConfig) challengeError(w http.ResponseWriter, challengeErr error) {
+	w.Header().Set("HX-Reswap", "none")
+
+	msg, err := utils.TemplString(components.ChallengeError(challengeErr.Error()))
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+		return
+	}
+
+	_, err = fmt.Fprint(w, msg)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+	}
+}
+
+// challengeStruct describes the challenge from the point of view of the user
+// looking at it.
+func challengeStruct(r *http.Request, c challenges.Challenge, userId uuid.UUID, now time.Time) components.ChallengeStruct {
+	timeControl := fmt.Sprintf("%v min", c.FullTime/60)
+	if c.Addition != 0 {
+		timeControl = fmt.Sprintf("%v + %v sec", timeControl, c.Addition)
+	}
+
+	opponent := c.From.Name
+	if c.From.ID == userId {
+		opponent = c.ToName
+	}
+
+	color := "you play " + c.Color
+	switch {
+	case c.Color == challenges.ColorRandom:
+		color = "random colors"
+	case c.From.ID != userId && c.Color == challenges.ColorWhite:
+		color = "you play " + challenges.ColorBlack
+	case c.From.ID != userId:
+		color = "you play " + challenges.ColorWhite
+	}
+
+	var link string
+	if c.IsInvite() {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		link = fmt.Sprintf("%v://%v/challenge/%v", scheme, r.Host, c.ID)
+	}
+
+	return components.ChallengeStruct{
+		ID:          c.ID,
+		Opponent:    opponent,
+		TimeControl: timeControl,
+		Color:       color,
+		Link:        link,
+		ExpiresIn:   utils.FormatTime(int(c.ExpiresAt.Sub(now).Seconds())),
+	}
+}
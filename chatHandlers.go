@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	errChatNotAllowed = errors.New("log in and join the game to chat")
+	errGuestChat      = errors.New("sign up to chat, guests can only read it")
+)
+
+// chatMessage handles the chat form, which htmx sends over the websocket as
+// JSON, for one channel of the match.
+func (cfg *appConfig) chatMessage(currentGame, channel string) func(uuid.UUID, []byte) {
+	return func(userId uuid.UUID, data []byte) {
+		var payload struct {
+			Chat string `json:"chat"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			responses.LogError("couldn't decode chat message", err)
+			return
+		}
+
+		err := cfg.sendChat(currentGame, userId, channel, payload.Chat)
+		if err == nil {
+			return
+		}
+
+		msg, renderErr := utils.TemplString(components.ChatError(err.Error()))
+		if renderErr != nil {
+			responses.LogError("couldn't render chat error", renderErr)
+			return
+		}
+
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			if channelHub, _ := match.ChatChannel(channel); channelHub != nil {
+				channelHub.Send(userId, msg)
+			}
+		})
+	}
+}
+
+func (cfg *appConfig) sendChat(currentGame string, userId uuid.UUID, channel, text string) error {
+	text, err := chat.Validate(text)
+	if err != nil {
+		return err
+	}
+
+	// Chat messages are kept with the account that wrote them, so guests
+	// only get to read the chat.
+	var guest bool
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		for _, player := range match.Online.Players {
+			if player.ID == userId && player.Guest {
+				guest = true
+			}
+		}
+	})
+	if guest {
+		return errGuestChat
+	}
+
+	user, err := cfg.database.GetUserById(context.Background(), userId)
+	if err != nil {
+		return errChatNotAllowed
+	}
+
+	var member bool
+	var allowed bool
+	var matchId int32
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		channelHub, listeners := match.ChatChannel(channel)
+		if channelHub == nil || match.Online.Chat == nil || !slices.Contains(listeners, userId) {
+			return
+		}
+		member = true
+
+		if !match.Online.Chat.Allow(userId, time.Now()) {
+			return
+		}
+		allowed = true
+
+		text = cfg.chatFilter.Clean(text)
+		message := components.ChatMessageStruct{
+			UserID:  userId,
+			Name:    user.Name,
+			Text:    text,
+			Channel: channel,
+		}
+		watchPath := matches.WatchPath(currentGame)
+
+		for _, id := range listeners {
+			if match.Online.Chat.IsMuted(id, userId) {
+				continue
+			}
+
+			msg, err := utils.TemplString(components.ChatLine(watchPath, message, id != userId))
+			if err != nil {
+				responses.LogError("couldn't render chat message", err)
+				return
+			}
+			if id == userId {
+				clear, err := utils.TemplString(components.ChatError(""))
+				if err != nil {
+					responses.LogError("couldn't render chat error", err)
+					return
+				}
+				msg = clear + msg
+			}
+
+			channelHub.Send(id, msg)
+		}
+
+		if channel == chat.ChannelPlayers {
+			match.Publish(protocol.NewChat(user.Name, text))
+		}
+
+		matchId = match.MatchId
+	})
+
+	if !member {
+		return errChatNotAllowed
+	}
+
+	if !allowed {
+		return chat.ErrRateLimited
+	}
+
+	err = cfg.database.CreateChatMessage(context.Background(), database.CreateChatMessageParams{
+		MatchID: matchId,
+		UserID:  userId,
+		Channel: channel,
+		Body:    text,
+	})
+	if err != nil {
+		responses.LogError("couldn't save chat message", err)
+	}
+
+	return nil
+}
+
+func (cfg *appConfig) chatHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame := matches.WatchGameKey(r.PathValue("id"))
+	channel := r.URL.Query().Get("channel")
+
+	viewerId, err := cfg.getUserId(r)
+	if err != nil {
+		viewerId = uuid.Nil
+	}
+
+	var matchId int32
+	var canRead bool
+	var room *chat.Room
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		_, listeners := match.ChatChannel(channel)
+		canRead = channel == chat.ChannelSpectators || slices.Contains(listeners, viewerId)
+		matchId = match.MatchId
+		room = match.Online.Chat
+	})
+
+	if !ok || room == nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
+		return
+	}
+
+	if !canRead {
+		responses.RespondWithAnError(w, http.StatusForbidden, "can't read this chat", fmt.Errorf("user %v can't read the %v chat of %v", viewerId, channel, currentGame))
+		return
+	}
+
+	messages, err := cfg.database.GetChatMessagesForMatch(r.Context(), matchId)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get chat messages", err)
+		return
+	}
+
+	watchPath := matches.WatchPath(currentGame)
+	for _, message := range messages {
+		if message.Channel != channel || room.IsMuted(viewerId, message.UserID) {
+			continue
+		}
+
+		err = components.ChatLine(watchPath, components.ChatMessageStruct{
+			UserID:  message.UserID,
+			Name:    message.Name,
+			Text:    message.Body,
+			Channel: message.Channel,
+		}, viewerId != uuid.Nil && message.UserID != viewerId).Render(r.Context(), w)
+
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+			return
+		}
+	}
+}
+
+func (cfg *appConfig) muteHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame := matches.WatchGameKey(r.PathValue("id"))
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't decode request", err)
+		return
+	}
+
+	mutedId, err := uuid.Parse(r.FormValue("user"))
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "invalid user", err)
+		return
+	}
+
+	var muted bool
+	var live bool
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Online.Chat == nil {
+			return
+		}
+		live = true
+		muted = match.Online.Chat.ToggleMute(userId, mutedId)
+	})
+
+	if !live {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
+		return
+	}
+
+	err = components.MuteButton(matches.WatchPath(currentGame), mutedId, muted).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/google/uuid"
+)
+
+func TestSendChatGuestsOnlyRead(t *testing.T) {
+	cfg := &appConfig{Matches: matches.NewMatches()}
+
+	guest := uuid.New()
+	currentGame := "online:abc123"
+	cfg.Matches.SetMatch(currentGame, matches.Match{
+		IsOnline: true,
+		Online: matches.OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: guest, Pieces: "white", Guest: true},
+				"black": {ID: uuid.New(), Pieces: "black"},
+			},
+		},
+	})
+
+	err := cfg.sendChat(currentGame, guest, chat.ChannelPlayers, "good luck")
+	if !errors.Is(err, errGuestChat) {
+		t.Errorf("sendChat() error = %v, want %v", err, errGuestChat)
+	}
+}
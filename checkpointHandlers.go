@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// checkpointMatch saves a game in progress, so a restart doesn't lose it.
+// A checkpoint older than the one already saved is dropped by the query.
+func (cfg *appConfig) checkpointMatch(ctx context.Context, currentGame string, match matches.Match) {
+	if !match.Checkpointed(currentGame) || match.Result != "" {
+		return
+	}
+
+	state, err := json.Marshal(match.Checkpoint())
+	if err != nil {
+		responses.LogError("couldn't encode the game", err)
+		return
+	}
+
+	err = cfg.database.SaveLiveMatch(ctx, database.SaveLiveMatchParams{
+		Game:  currentGame,
+		Ply:   int32(len(match.AllMoves)),
+		State: state,
+	})
+	if err != nil {
+		responses.LogError("couldn't checkpoint the game", err)
+	}
+}
+
+// rewindCheckpoint replaces the checkpoint of a game whose moves were taken
+// back, which the ply check would otherwise keep.
+func (cfg *appConfig) rewindCheckpoint(ctx context.Context, currentGame string) {
+	match, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok || !matches.Durable(currentGame) {
+		return
+	}
+
+	err := cfg.database.DeleteLiveMatch(ctx, currentGame)
+	if err != nil {
+		responses.LogError("couldn't drop the checkpoint", err)
+		return
+	}
+
+	cfg.checkpointMatch(ctx, currentGame, match)
+}
+
+// matchMoved runs after every move played in any match.
+func (cfg *appConfig) matchMoved(currentGame string, match matches.Match) {
+	cfg.checkpointMatch(context.Background(), currentGame, match)
+}
+
+// restoreMatches picks up the games that were still being played when the
+// server went down. Their clocks start again from where they were saved.
+// Checkpoints nobody has played on for too long are dropped instead.
+func (cfg *appConfig) restoreMatches(ctx context.Context) error {
+	saved, err := cfg.database.GetLiveMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, live := range saved {
+		var checkpoint matches.Checkpoint
+		err = json.Unmarshal(live.State, &checkpoint)
+		if err != nil {
+			responses.LogError("couldn't decode the saved game "+live.Game, err)
+			continue
+		}
+
+		match := checkpoint.Restore(now)
+		if !match.Checkpointed(live.Game) || matches.CheckpointExpired(live.UpdatedAt, now) {
+			err = cfg.database.DeleteLiveMatch(ctx, live.Game)
+			if err != nil {
+				responses.LogError("couldn't drop the checkpoint "+live.Game, err)
+			}
+			continue
+		}
+
+		if match.IsOnline {
+			online := cfg.newOnlineGame(live.Game)
+			maps.Copy(online.Players, match.Online.Players)
+			match.Online = online
+		}
+
+		cfg.Matches.SetMatch(live.Game, match)
+
+		if match.IsOnline {
+			cfg.watchFirstMove(live.Game)
+			cfg.awaitPlayers(live.Game)
+		}
+	}
+
+	return nil
+}
+
+// awaitPlayers gives the players of a restored online game the usual grace
+// period to reconnect. A game neither of them comes back to is aborted, one
+// only the opponent comes back to is lost by abandonment.
+func (cfg *appConfig) awaitPlayers(currentGame string) {
+	var players []uuid.UUID
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		for _, player := range match.Online.Players {
+			match.MarkDisconnected(player.ID, time.Now())
+			players = append(players, player.ID)
+		}
+	})
+
+	time.AfterFunc(matches.ReconnectGracePeriod, func() {
+		var finished matches.Match
+		var abandoned, aborted bool
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			if match.Result != "" {
+				return
+			}
+
+			now := time.Now()
+			aborted = true
+			for _, id := range players {
+				expired := match.ReconnectGraceExpired(id, now)
+				abandoned = abandoned || expired
+				aborted = aborted && expired
+			}
+			if !aborted {
+				return
+			}
+
+			msg, err := utils.TemplString(components.GameAborted())
+			if err != nil {
+				responses.LogError("couldn't render aborted game", err)
+			} else {
+				match.Online.Hub.Broadcast(msg)
+			}
+			match.PublishGameEnd("*", matches.TerminationAborted)
+
+			finished = match.Clone()
+		})
+
+		if aborted {
+			cfg.finishOnlineMatch(currentGame, finished)
+			return
+		}
+
+		if abandoned {
+			for _, id := range players {
+				cfg.adjudicateAbandonment(currentGame, id, false)
+			}
+		}
+	})
+}
+
+// shutdown stops taking requests, checkpoints the games still being played
+// with the running turn charged to the player on move and closes every
+// websocket, so the pages know to reconnect once the server is back.
+func (cfg *appConfig) shutdown(server *http.Server, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err != nil {
+		responses.LogError("couldn't drain the requests", err)
+	}
+
+	for _, currentGame := range cfg.Matches.GetAllMatches() {
+		var match matches.Match
+		ok := cfg.Matches.Do(currentGame, func(m *matches.Match) {
+			m.ChargeRunningTurn(time.Now())
+			match = m.Clone()
+		})
+		if !ok {
+			continue
+		}
+
+		cfg.checkpointMatch(context.Background(), currentGame, match)
+
+		if match.IsOnline {
+			err = match.Online.Shutdown(ctx)
+			if err != nil {
+				responses.LogError("couldn't drain the game "+currentGame, err)
+			}
+		}
+	}
+
+	err = cfg.Seekers.Shutdown(ctx)
+	if err != nil {
+		responses.LogError("couldn't drain the seekers", err)
+	}
+}
@@ -8,7 +8,7 @@ package components
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-func Signup() templ.Component {
+func Signup(claim string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -29,7 +29,45 @@ func Signup() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"flex justify-between items-center mb-6\"><h2 class=\"text-xl font-semibold text-gray-100\" id=\"modal-title\">Signup</h2><button hx-get=\"/close-modal\" class=\"text-gray-400 hover:text-gray-200 text-2xl leading-none\">&times;</button></div><form id=\"auth-form\" hx-post=\"/auth-signup\" hx-target=\"#modal-content\" hx-swap=\"none\" class=\"space-y-4\"><div><div><label class=\"block text-sm font-medium text-gray-300\">Name</label> <input type=\"name\" name=\"name\" required autocomplete=\"name\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div class=\"mt-2\"><label class=\"block text-sm font-medium text-gray-300\">Email</label> <input type=\"email\" name=\"email\" required autocomplete=\"email\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div id=\"password\" hx-swap class=\"mt-2\"><label class=\"block text-sm font-medium text-gray-300\">Password</label> <input type=\"password\" name=\"password\" required autocomplete=\"current-password\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div id=\"incorrect-password\"></div><button type=\"submit\" class=\"w-full mt-4 bg-amber-500 hover:bg-amber-600 text-white py-2 rounded transition\">Signup</button></div></form><div class=\"text-sm text-center mt-4 text-gray-300\">Already have an account? <button hx-get=\"/login-modal\" hx-target=\"#modal-content\" hx-swap=\"innerHTML\" class=\"text-emerald-400 hover:underline\">Log in</button></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"flex justify-between items-center mb-6\"><h2 class=\"text-xl font-semibold text-gray-100\" id=\"modal-title\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if claim != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "Sign up to keep your game")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "Signup")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</h2><button hx-get=\"/close-modal\" class=\"text-gray-400 hover:text-gray-200 text-2xl leading-none\">&times;</button></div><form id=\"auth-form\" hx-post=\"/auth-signup\" hx-target=\"#modal-content\" hx-swap=\"none\" class=\"space-y-4\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if claim != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<input type=\"hidden\" name=\"claim\" value=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var2 string
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(claim)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `containers/components/signup.templ`, Line: 17, Col: 55}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div><div><label class=\"block text-sm font-medium text-gray-300\">Name</label> <input type=\"name\" name=\"name\" required autocomplete=\"name\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div class=\"mt-2\"><label class=\"block text-sm font-medium text-gray-300\">Email</label> <input type=\"email\" name=\"email\" required autocomplete=\"email\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div id=\"password\" hx-swap class=\"mt-2\"><label class=\"block text-sm font-medium text-gray-300\">Password</label> <input type=\"password\" name=\"password\" required autocomplete=\"current-password\" class=\"mt-1 w-full bg-[#2c2926] border border-gray-600 rounded px-3 py-2 text-gray-100 focus:outline-none focus:ring-2 focus:ring-amber-600\"></div><div id=\"incorrect-password\"></div><button type=\"submit\" class=\"w-full mt-4 bg-amber-500 hover:bg-amber-600 text-white py-2 rounded transition\">Signup</button></div></form><div class=\"text-sm text-center mt-4 text-gray-300\">Already have an account? <button hx-get=\"/login-modal\" hx-target=\"#modal-content\" hx-swap=\"innerHTML\" class=\"text-emerald-400 hover:underline\">Log in</button></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -8,7 +8,7 @@ package components
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-func StartGameRight() templ.Component {
+func StartGameRight(abortable bool) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -29,7 +29,17 @@ func StartGameRight() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"xl:!w-[240px] mt-10 w-board w-board-md xl:mx-0 mx-auto\"><div><button hx-get=\"/surrender\" hx-confirm=\"Are you sure you want to surrender\" class=\"bg-red-600 hover:bg-red-500 text-white font-medium py-2 px-5 rounded-md shadow-sm transition duration-200 cursor-pointer mt-8\">Surrender</button></div><div><button id=\"offer-draw\" hx-get=\"/offer-draw\" hx-confirm=\"Are you sure you want to offer draw\" class=\"bg-gray-600 hover:bg-gray-500 text-white font-medium py-2 px-5 rounded-md shadow-sm transition duration-200 cursor-pointer mt-8\" hx-target=\"this\">Offer Draw</button></div><div hx-get=\"/all-moves\" hx-trigger=\"load\"></div><div id=\"moves\" class=\"grid grid-cols-3 text-white h-moves w-[240px] mt-8 max-h-[320px] overflow-auto\"></div></div><div id=\"overlay\" hx-swap-oob=\"true\" class=\"hidden w-board w-board-md h-board h-board-md absolute z-20 hover:cursor-default\"></div><div id=\"timer-update\" hx-get=\"/timer\" hx-trigger=\"every 1s\" hx-swap-oob=\"true\"></div><div id=\"left-side\" hx-swap-oob=\"true\" class=\"w-[240px]\"></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"xl:!w-[240px] mt-10 w-board w-board-md xl:mx-0 mx-auto\"><div><button hx-get=\"/surrender\" hx-confirm=\"Are you sure you want to surrender\" class=\"bg-red-600 hover:bg-red-500 text-white font-medium py-2 px-5 rounded-md shadow-sm transition duration-200 cursor-pointer mt-8\">Surrender</button></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if abortable {
+			templ_7745c5c3_Err = AbortButton().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div><button id=\"offer-draw\" hx-get=\"/offer-draw\" hx-confirm=\"Are you sure you want to offer draw\" hx-swap=\"none\" class=\"bg-gray-600 hover:bg-gray-500 text-white font-medium py-2 px-5 rounded-md shadow-sm transition duration-200 cursor-pointer mt-8\">Offer Draw</button></div><div><button hx-get=\"/takeback\" hx-swap=\"none\" class=\"bg-gray-600 hover:bg-gray-500 text-white font-medium py-2 px-5 rounded-md shadow-sm transition duration-200 cursor-pointer mt-8\">Takeback</button></div><div id=\"cancel-premoves\"></div><div id=\"claim-draw\"></div><div id=\"draw-offer\"></div><div hx-get=\"/all-moves\" hx-trigger=\"load\"></div><div id=\"moves\" class=\"grid grid-cols-3 text-white h-moves w-[240px] mt-8 max-h-[320px] overflow-auto\"></div></div><div id=\"overlay\" hx-swap-oob=\"true\" class=\"hidden w-board w-board-md h-board h-board-md absolute z-20 hover:cursor-default\"></div><div id=\"timer-update\" hx-get=\"/timer\" hx-trigger=\"every 1s\" hx-swap-oob=\"true\"></div><div id=\"left-side\" hx-swap-oob=\"true\" class=\"w-[240px]\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
 )
 
 var cols = [8]string{"a", "b", "c", "d", "e", "f", "g", "h"}
@@ -38,25 +37,86 @@ type PlayerStruct struct {
 }
 
 type OnlinePlayerStruct struct {
-	ID             uuid.UUID
-	Name           string
-	Image          string
-	Timer          string
-	Pieces         string
-	Conn           *websocket.Conn
-	ReconnectTimer int8
-	Multiplier     int
+	ID         uuid.UUID
+	Name       string
+	Image      string
+	Timer      string
+	Pieces     string
+	Multiplier int
+	Guest      bool
+}
+
+// MoveStateStruct is what the board sends with every move: how many half
+// moves the page has seen and the square of the selected piece.
+type MoveStateStruct struct {
+	Ply  int
+	From string
 }
 
 type MatchStruct struct {
-	White   string
-	Black   string
-	Ended   bool
-	Date    string
-	NoMoves int
-	Result  string
-	Online  bool
-	MatchId int
+	White       string
+	Black       string
+	Ended       bool
+	Date        string
+	NoMoves     int
+	Result      string
+	Online      bool
+	MatchId     int
+	Termination string
+	TimeControl string
+	Variant     string
+	Rated       bool
+	EndedAt     string
+}
+
+type MoveTimeStruct struct {
+	Move      string
+	TimeSpent int
+	DrawOffer bool
+}
+
+type ChatMessageStruct struct {
+	UserID  uuid.UUID
+	Name    string
+	Text    string
+	Channel string
+}
+
+type ChallengeStruct struct {
+	ID          string
+	Opponent    string
+	TimeControl string
+	Color       string
+	Link        string
+	ExpiresIn   string
+}
+
+type SeekStruct struct {
+	ID          string
+	Name        string
+	Rating      int
+	Provisional bool
+	TimeControl string
+	Variant     string
+	Color       string
+	Rated       bool
+	Own         bool
+}
+
+type ChartBar struct {
+	X       int
+	Y       int
+	Width   int
+	Height  int
+	IsWhite bool
+	Ply     int
+	Seconds int
+}
+
+type TimeChart struct {
+	Width  int
+	Height int
+	Bars   []ChartBar
 }
 
 func genCol(color string) string {
@@ -74,3 +134,18 @@ func getPosY(row, multiplier int) string {
 func getPiecePos(cord [2]int) string {
 	return fmt.Sprintf("bottom: %vpx; left: %vpx", cord[0], cord[1])
 }
+
+func formatSpent(seconds int) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%vs", seconds)
+	}
+	return fmt.Sprintf("%v:%02d", seconds/60, seconds%60)
+}
+
+func chartTitle(bar ChartBar) string {
+	moveNumber := (bar.Ply-1)/2 + 1
+	if bar.IsWhite {
+		return fmt.Sprintf("%v. %v", moveNumber, formatSpent(bar.Seconds))
+	}
+	return fmt.Sprintf("%v... %v", moveNumber, formatSpent(bar.Seconds))
+}
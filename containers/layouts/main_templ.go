@@ -11,7 +11,7 @@ import templruntime "github.com/a-h/templ/runtime"
 import "github.com/NikolaTosic-sudo/chess-live/containers/components"
 
 func MainPage(chessBoard map[string]components.Square, pieces map[string]components.Piece, multiplier int, whitePlayer,
-	blackPlayer components.PlayerStruct, whiteLostPieces, blackLostPieces []string) templ.Component {
+	blackPlayer components.PlayerStruct, whiteLostPieces, blackLostPieces []string, moveState components.MoveStateStruct) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -53,7 +53,7 @@ func MainPage(chessBoard map[string]components.Square, pieces map[string]compone
 				return templ_7745c5c3_Err
 			}
 			templ_7745c5c3_Err = components.GridBoard(chessBoard, pieces, multiplier, whitePlayer, blackPlayer, whiteLostPieces,
-				blackLostPieces).Render(ctx, templ_7745c5c3_Buffer)
+				blackLostPieces, moveState).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
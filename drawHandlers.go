@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// offerDrawHandler offers a draw, or agrees to one when the opponent offered
+// first. Both sides of a local game share the screen, so the offer is shown
+// there for the opponent to answer.
+func (cfg *appConfig) offerDrawHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	// Local games don't tell their players apart, so only online games need
+	// to know who is asking.
+	userId, _ := cfg.getUserId(r)
+
+	err = cfg.offerDraw(r.Context(), w, currentGame, userId)
+	respondToDraw(w, err)
+}
+
+// offerDraw offers a draw for userId, or agrees to the one offered by the
+// opponent. The boards of an online game get the offer over the hub, w gets
+// the one of a local game.
+func (cfg *appConfig) offerDraw(ctx context.Context, w io.Writer, currentGame string, userId uuid.UUID) error {
+	endMsg, err := utils.TemplString(components.DrawEnded())
+	if err != nil {
+		return fmt.Errorf("error converting component to string: %w", err)
+	}
+
+	var accepted, online, onPlayedMove, stores bool
+	var offer matches.DrawOffer
+	var matchId int32
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		accepted, err = match.OfferDraw(userId)
+		if err != nil {
+			return
+		}
+
+		if accepted {
+			err = match.SendToPlayers(w, endMsg)
+			return
+		}
+
+		online = match.IsOnline
+		offer = match.DrawOffer
+		onPlayedMove = offer.Ply >= 0 && offer.Ply == len(match.AllMoves)-1
+		matchId = match.MatchId
+		stores = storesMoves(currentGame, match)
+
+		for color, player := range match.Online.Players {
+			box, err := utils.TemplString(components.DrawOfferBox(offer.By, color != offer.By))
+			if err != nil {
+				responses.LogError("couldn't render draw offer", err)
+				continue
+			}
+			match.Online.Hub.Send(player.ID, box)
+		}
+	})
+
+	if !ok {
+		return fmt.Errorf("%w: %v", errGameNotFound, currentGame)
+	}
+	if err != nil || accepted {
+		return err
+	}
+
+	// An offer made after the offerer's move goes with that move, which is
+	// already stored. Resumed games don't store their moves.
+	if onPlayedMove && stores {
+		err = cfg.database.MarkLatestMoveDrawOffer(ctx, matchId)
+		if err != nil {
+			return fmt.Errorf("couldn't record the draw offer: %w", err)
+		}
+	}
+
+	if !online {
+		err = components.DrawOfferBox(offer.By, true).Render(ctx, w)
+		if err != nil {
+			return fmt.Errorf("couldn't render template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *appConfig) declineDrawHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, _ := cfg.getUserId(r)
+
+	cleared, err := utils.TemplString(components.DrawOfferBox("", false))
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+		return
+	}
+
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = match.DeclineDraw(userId)
+		if err != nil {
+			return
+		}
+
+		err = match.SendToPlayers(w, cleared)
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+	}
+}
+
+func (cfg *appConfig) accpetDrawHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, _ := cfg.getUserId(r)
+
+	endMsg, err := utils.TemplString(components.DrawEnded())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+		return
+	}
+
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = match.AcceptDraw(userId)
+		if err != nil {
+			return
+		}
+
+		err = match.SendToPlayers(w, endMsg)
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+	}
+}
+
+// claimDrawHandler ends the game in a draw by threefold repetition or the
+// fifty-move rule, for the player to move.
+func (cfg *appConfig) claimDrawHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, _ := cfg.getUserId(r)
+
+	err = cfg.claimDraw(w, currentGame, userId)
+	respondToDraw(w, err)
+}
+
+func (cfg *appConfig) claimDraw(w io.Writer, currentGame string, userId uuid.UUID) error {
+	endMsg, err := utils.TemplString(components.DrawEnded())
+	if err != nil {
+		return fmt.Errorf("error converting component to string: %w", err)
+	}
+
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		_, err = match.ClaimDraw(userId)
+		if err != nil {
+			return
+		}
+
+		err = match.SendToPlayers(w, endMsg)
+	})
+
+	if !ok {
+		return fmt.Errorf("%w: %v", errGameNotFound, currentGame)
+	}
+
+	return err
+}
+
+// respondToDraw answers a draw offer or claim that couldn't be made.
+func respondToDraw(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, errGameNotFound):
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+	case errors.Is(err, matches.ErrDrawOfferPending), errors.Is(err, matches.ErrDrawOfferTooSoon),
+		errors.Is(err, matches.ErrNoDrawClaim), errors.Is(err, matches.ErrNotAPlayer),
+		errors.Is(err, matches.ErrNotYourTurn), errors.Is(err, matches.ErrMatchOver):
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+	default:
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't settle the draw", err)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/google/uuid"
+)
+
+var promotionPieces = map[byte]string{
+	'q': "%v_queen",
+	'r': "right_%v_rook",
+	'b': "right_%v_bishop",
+	'n': "right_%v_knight",
+}
+
+func (cfg *appConfig) feedHandler(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		if c, err := r.Cookie("access_token"); err == nil {
+			token = c.Value
+		}
+	}
+
+	userId, err := auth.ValidateJWT(token, cfg.secret)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	currentGame := matches.GameKey(strings.TrimPrefix(r.URL.Query().Get("game"), "online:"))
+
+	var game matches.OnlineGame
+	var isPlayer bool
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		game = match.Online
+		isPlayer = match.IsPlayer(userId)
+	})
+
+	if !ok || game.Feed == nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
+		return
+	}
+
+	if !isPlayer {
+		responses.RespondWithAnError(w, http.StatusForbidden, "not a player in this game", fmt.Errorf("user %v isn't playing %v", userId, currentGame))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		responses.LogError("websocket upgrade failed", err)
+		return
+	}
+
+	game.Feed.Serve(userId, conn)
+}
+
+func (cfg *appConfig) sendGameFull(currentGame string) func(uuid.UUID) {
+	return func(userId uuid.UUID) {
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			msg, err := protocol.Encode(match.GameFull(currentGame))
+			if err != nil {
+				responses.LogError("couldn't encode game state", err)
+				return
+			}
+			match.Online.Feed.Send(userId, msg)
+		})
+	}
+}
+
+func (cfg *appConfig) feedCommand(currentGame string) func(uuid.UUID, []byte) {
+	return func(userId uuid.UUID, data []byte) {
+		cmd, err := protocol.DecodeCommand(data)
+		if err == nil {
+			switch cmd.Type {
+			case protocol.CommandMove:
+				err = cfg.playFeedMove(currentGame, userId, cmd.Uci)
+			case protocol.CommandResign:
+				err = cfg.resignFromFeed(currentGame, userId)
+			case protocol.CommandAbort:
+				err = cfg.abortMatch(currentGame, userId)
+			case protocol.CommandOfferDraw:
+				err = cfg.offerDraw(context.Background(), io.Discard, currentGame, userId)
+			case protocol.CommandClaimDraw:
+				err = cfg.claimDraw(io.Discard, currentGame, userId)
+			case protocol.CommandChat:
+				err = cfg.sendChat(currentGame, userId, chat.ChannelPlayers, cmd.Text)
+			}
+		}
+
+		if err == nil {
+			return
+		}
+
+		msg, encodeErr := protocol.Encode(protocol.NewError(err.Error()))
+		if encodeErr != nil {
+			responses.LogError("couldn't encode feed error", encodeErr)
+			return
+		}
+
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			match.Online.Feed.Send(userId, msg)
+		})
+	}
+}
+
+// playFeedMove plays a UCI move for a feed client. It goes through the same
+// steps as the clicks on the board, so the move follows exactly the same
+// rules.
+func (cfg *appConfig) playFeedMove(currentGame string, userId uuid.UUID, uci string) error {
+	var err error
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = cfg.playUci(context.Background(), match, currentGame, userId, uci)
+		if err == nil {
+			cfg.playPremoves(context.Background(), match, currentGame)
+		}
+	})
+	if !ok {
+		return fmt.Errorf("%w: %v", errGameNotFound, currentGame)
+	}
+
+	return err
+}
+
+// playUci plays a UCI move, a feed client's or a premove, on the match.
+func (cfg *appConfig) playUci(ctx context.Context, match *matches.Match, currentGame string, userId uuid.UUID, uci string) error {
+	// Off turn the clicks below would queue premoves instead.
+	if match.Premoving(userId) {
+		return matches.ErrNotYourTurn
+	}
+
+	from := matches.TileName(uci[:2])
+	to := matches.TileName(uci[2:4])
+
+	match.ClearSelection()
+
+	piece := match.Board[from].Piece
+	if piece.Name == "" {
+		return fmt.Errorf("no piece on %v", uci[:2])
+	}
+	targetName := match.Board[to].Piece.Name
+	color := "black"
+	if piece.IsWhite {
+		color = "white"
+	}
+
+	if piece.IsKing && (uci[0]-uci[2] == 2 || uci[2]-uci[0] == 2) {
+		rookFile := "h"
+		if uci[2] == 'c' {
+			rookFile = "a"
+		}
+		targetName = match.Board[string(from[0])+rookFile].Piece.Name
+	}
+
+	multiplier := playerMultiplier(match, userId)
+	played := len(match.UciMoves)
+	cmd := matches.MoveCommand{Player: userId, Ply: len(match.AllMoves), From: from}
+
+	err := cfg.clickPiece(ctx, io.Discard, match, currentGame, cmd, piece.Name, multiplier)
+	if err == nil && targetName != "" {
+		err = cfg.clickPiece(ctx, io.Discard, match, currentGame, cmd, targetName, multiplier)
+	} else if err == nil {
+		cmd.To = to
+		err = cfg.moveTo(ctx, io.Discard, match, currentGame, cmd, multiplier)
+	}
+
+	if err == nil && len(uci) == 5 {
+		promotion, ok := promotionPieces[uci[4]]
+		if !ok {
+			err = fmt.Errorf("unknown promotion piece %q", uci[4:])
+		} else {
+			cmd.Ply++
+			err = cfg.promote(io.Discard, match, currentGame, cmd, piece.Name, fmt.Sprintf(promotion, color), multiplier)
+		}
+	}
+
+	if err == nil && len(match.UciMoves) == played {
+		err = fmt.Errorf("%w %v", errIllegalMove, uci)
+	}
+	if err != nil {
+		match.ClearSelection()
+	}
+
+	return err
+}
+
+func (cfg *appConfig) resignFromFeed(currentGame string, userId uuid.UUID) error {
+	_, _, err := cfg.resign(currentGame, userId)
+	if err != nil {
+		return err
+	}
+
+	finished, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok {
+		return fmt.Errorf("%w: %v", errGameNotFound, currentGame)
+	}
+
+	cfg.finishOnlineMatch(currentGame, finished)
+
+	return nil
+}
@@ -1,27 +1,36 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"maps"
+	"io"
 	"net/http"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
-	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
 	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
 	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
+)
+
+var (
+	// errIllegalMove is a click that doesn't make a move, which the board
+	// just ignores.
+	errIllegalMove      = errors.New("illegal move")
+	errInvalidPromotion = errors.New("invalid promotion")
+	errGameNotFound     = errors.New("game not found")
 )
 
 func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 	currentPieceName := r.Header.Get("Hx-Trigger")
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", err)
 		return
@@ -39,16 +48,34 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't convert multiplier", err)
 		return
 	}
-	currentGame := c.Value
-	match, _ := cfg.Matches.GetMatch(currentGame)
-	onlineGame, found := match.IsOnlineMatch()
-	currentPiece := match.Pieces[currentPieceName]
-	userC, err := r.Cookie("access_token")
+	cmd := cfg.moveCommand(r)
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Premoving(cmd.Player) {
+			premoveClick(w, match, cmd.Player, match.Pieces[currentPieceName].Tile)
+			return
+		}
 
-	var userId uuid.UUID
-	if err == nil && userC.Value != "" {
-		userId, _ = auth.ValidateJWT(userC.Value, cfg.secret)
+		err = cfg.clickPiece(r.Context(), w, match, currentGame, cmd, currentPieceName, multiplier)
+		if err == nil {
+			cfg.playPremoves(r.Context(), match, currentGame)
+		}
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
 	}
+	respondToMove(w, err)
+}
+
+// clickPiece handles a click on a piece. The player's own piece gets picked
+// up, dropped or castled with, the opponent's gets captured by the piece
+// picked up before.
+func (cfg *appConfig) clickPiece(ctx context.Context, w io.Writer, match *matches.Match, currentGame string, cmd matches.MoveCommand, currentPieceName string, multiplier int) error {
+	onlineGame, _ := match.IsOnlineMatch()
+	currentPiece := match.Pieces[currentPieceName]
+	userId := cmd.Player
+	cmd.To = currentPiece.Tile
 
 	canPlay := match.CanPlay(currentPiece, onlineGame.Players, userId)
 
@@ -59,22 +86,19 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 	legalMoves := match.CheckLegalMoves()
 
 	if matches.CanEat(match.SelectedPiece, currentPiece) && slices.Contains(legalMoves, currentSquareName) {
-		if found {
-			if match.IsWhiteTurn && onlineGame.Players["white"].ID != userId {
-				return
-			} else if !match.IsWhiteTurn && onlineGame.Players["black"].ID != userId {
-				return
-			}
+		err := match.Authorize(cmd)
+		if err != nil {
+			return err
 		}
+		defer match.RememberMove(cmd, match.Snapshot())
+
 		var kingCheck bool
 		if match.SelectedPiece.IsKing {
 			kingCheck = match.HandleChecksWhenKingMoves(currentSquareName)
 		} else if match.IsWhiteTurn && match.IsWhiteUnderCheck && !slices.Contains(match.TilesUnderAttack, currentSquareName) {
-			w.WriteHeader(http.StatusNoContent)
-			return
+			return errIllegalMove
 		} else if !match.IsWhiteTurn && match.IsBlackUnderCheck && !slices.Contains(match.TilesUnderAttack, currentSquareName) {
-			w.WriteHeader(http.StatusNoContent)
-			return
+			return errIllegalMove
 		}
 
 		var check bool
@@ -83,8 +107,7 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if check || kingCheck {
-			w.WriteHeader(http.StatusNoContent)
-			return
+			return errIllegalMove
 		}
 		var userColor string
 		if match.IsWhiteTurn {
@@ -113,33 +136,28 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		})
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't print to page", err)
-			return
+			return fmt.Errorf("couldn't print to page: %w", err)
 		}
 
 		match.SelectedPiece.Moved = true
 		_, saveSelected := match.EatCleanup(currentPiece, selectedSquare, currentSquareName)
 
-		cfg.Matches.SetMatch(currentGame, match)
-		err = cfg.showMoves(match, currentSquareName, saveSelected.Name, w, r)
+		err = cfg.showMoves(ctx, w, match, currentGame, currentSquareName, saveSelected.Name)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "show moves error: ", err)
-			return
+			return fmt.Errorf("show moves error: %w", err)
 		}
 		pawnPromotion, err := match.CheckForPawnPromotion(saveSelected.Name, w, userId)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "pawn promotion error: ", err)
-			return
+			return fmt.Errorf("pawn promotion error: %w", err)
 		}
 
 		if saveSelected.IsPawn && pawnPromotion {
-			return
+			return nil
 		}
 
-		noCheck, err := match.HandleIfCheck(w, r, saveSelected)
+		noCheck, err := match.HandleIfCheck(w, multiplier, saveSelected)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "handle check error: ", err)
-			return
+			return fmt.Errorf("handle check error: %w", err)
 		}
 		if noCheck {
 			var kingName string
@@ -149,8 +167,7 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 				kingName = "black_king"
 			} else {
 				match.EndTurn(w)
-				cfg.Matches.SetMatch(currentGame, match)
-				return
+				return nil
 			}
 			match.IsWhiteUnderCheck = false
 			match.IsBlackUnderCheck = false
@@ -173,18 +190,15 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 			})
 
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-				return
+				return fmt.Errorf("couldn't write to page: %w", err)
 			}
 		}
 		match.EndTurn(w)
-		cfg.Matches.SetMatch(currentGame, match)
-		return
+		return nil
 	}
 
 	if !canPlay {
-		w.WriteHeader(http.StatusNoContent)
-		return
+		return errIllegalMove
 	}
 
 	if selectedSquare != "" && selectedSquare != currentSquareName && matches.SamePiece(match.SelectedPiece, currentPiece) {
@@ -192,12 +206,17 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		isCastle, kingCheck := match.CheckForCastle(currentPiece)
 
 		if isCastle && !match.IsBlackUnderCheck && !match.IsWhiteUnderCheck && !kingCheck {
+			err := match.Authorize(cmd)
+			if err != nil {
+				return err
+			}
+			defer match.RememberMove(cmd, match.Snapshot())
 
-			err := cfg.handleCastle(w, currentPiece, currentGame, r)
+			err = cfg.handleCastle(ctx, w, match, currentGame, currentPiece)
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "error with handling castle", err)
+				return fmt.Errorf("error with handling castle: %w", err)
 			}
-			return
+			return nil
 		}
 
 		var kingsName string
@@ -227,12 +246,12 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		)
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't send to page", err)
+			responses.LogError("couldn't send to page", err)
 		}
 
 		match.SelectedPiece = currentPiece
-		cfg.Matches.SetMatch(currentGame, match)
-		return
+		writeMoveState(w, match)
+		return nil
 	}
 
 	if currentSquare.Selected {
@@ -261,12 +280,11 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		)
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+			responses.LogError("couldn't write to page", err)
 		}
 
-		cfg.Matches.SetMatch(currentGame, match)
-
-		return
+		writeMoveState(w, match)
+		return nil
 	} else {
 		currentSquare.Selected = true
 		match.SelectedPiece = currentPiece
@@ -283,36 +301,63 @@ func (cfg *appConfig) moveHandler(w http.ResponseWriter, r *http.Request) {
 		)
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			return fmt.Errorf("couldn't write to page: %w", err)
 		}
-		cfg.Matches.SetMatch(currentGame, match)
-		return
+
+		writeMoveState(w, match)
+		return nil
 	}
 }
 
 func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 	currentSquareName := r.Header.Get("Hx-Trigger")
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", err)
 		return
 	}
-	currentGame := c.Value
-	match, _ := cfg.Matches.GetMatch(currentGame)
+	cmd := cfg.moveCommand(r)
+	cmd.To = currentSquareName
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Premoving(cmd.Player) {
+			premoveClick(w, match, cmd.Player, currentSquareName)
+			return
+		}
+
+		err = cfg.moveTo(r.Context(), w, match, currentGame, cmd, formMultiplier(r, match, cmd.Player))
+		if err == nil {
+			cfg.playPremoves(r.Context(), match, currentGame)
+		}
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+	respondToMove(w, err)
+}
+
+// moveTo moves the piece picked up before to the empty square cmd.To, taking
+// a pawn en passant on the way if that's where it goes.
+func (cfg *appConfig) moveTo(ctx context.Context, w io.Writer, match *matches.Match, currentGame string, cmd matches.MoveCommand, multiplier int) error {
+	err := match.Authorize(cmd)
+	if err != nil {
+		return err
+	}
+	defer match.RememberMove(cmd, match.Snapshot())
+
+	currentSquareName := cmd.To
+	userId := cmd.Player
 	currentSquare := match.Board[currentSquareName]
 	selectedSquare := match.SelectedPiece.Tile
 
 	legalMoves := match.CheckLegalMoves()
 
-	userId, _ := cfg.getUserId(r)
-
 	var kingCheck bool
 	if match.SelectedPiece.IsKing && slices.Contains(legalMoves, currentSquareName) {
 		kingCheck = match.HandleChecksWhenKingMoves(currentSquareName)
 	} else if !slices.Contains(legalMoves, currentSquareName) && !slices.Contains(legalMoves, fmt.Sprintf("enpessant_%v", currentSquareName)) {
-		w.WriteHeader(http.StatusNoContent)
-		return
+		return errIllegalMove
 	}
 
 	var check bool
@@ -321,8 +366,7 @@ func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if check || kingCheck {
-		w.WriteHeader(http.StatusNoContent)
-		return
+		return errIllegalMove
 	}
 
 	if slices.Contains(legalMoves, fmt.Sprintf("enpessant_%v", currentSquareName)) {
@@ -354,29 +398,25 @@ func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 			pieceToDelete.Image,
 		)
 
-		err = match.SendMessage(w, message, [2][]int{
+		err := match.SendMessage(w, message, [2][]int{
 			{currentSquare.CoordinatePosition[0]},
 			{currentSquare.CoordinatePosition[1]},
 		})
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't print to page", err)
-			return
+			return fmt.Errorf("couldn't print to page: %w", err)
 		}
 
 		squareToDelete, saveSelected := match.EatCleanup(pieceToDelete, squareToDeleteName, currentSquareName)
 
-		cfg.Matches.SetMatch(currentGame, match)
-		err = cfg.showMoves(match, currentSquareName, saveSelected.Name, w, r)
+		err = cfg.showMoves(ctx, w, match, currentGame, currentSquareName, saveSelected.Name)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "show moves error: ", err)
-			return
+			return fmt.Errorf("show moves error: %w", err)
 		}
 
-		noCheck, err := match.HandleIfCheck(w, r, saveSelected)
+		noCheck, err := match.HandleIfCheck(w, multiplier, saveSelected)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "handle check error: ", err)
-			return
+			return fmt.Errorf("handle check error: %w", err)
 		}
 		if noCheck {
 			var kingName string
@@ -386,8 +426,7 @@ func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 				kingName = "black_king"
 			} else {
 				match.EndTurn(w)
-				cfg.Matches.SetMatch(currentGame, match)
-				return
+				return nil
 			}
 			match.IsWhiteUnderCheck = false
 			match.IsBlackUnderCheck = false
@@ -410,14 +449,12 @@ func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 			})
 
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-				return
+				return fmt.Errorf("couldn't write to page: %w", err)
 			}
 		}
 
 		match.EndTurn(w)
-		cfg.Matches.SetMatch(currentGame, match)
-		return
+		return nil
 	}
 
 	if selectedSquare != "" && selectedSquare != currentSquareName {
@@ -430,74 +467,86 @@ func (cfg *appConfig) moveToHandler(w http.ResponseWriter, r *http.Request) {
 			"",
 		)
 
-		err = match.SendMessage(w, message, [2][]int{
+		err := match.SendMessage(w, message, [2][]int{
 			{currentSquare.CoordinatePosition[0]},
 			{currentSquare.CoordinatePosition[1]},
 		})
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			return fmt.Errorf("couldn't write to page: %w", err)
 		}
 		match.CheckForEnPessant(selectedSquare, currentSquare)
 		saveSelected := match.SelectedPiece
 		match.AllMoves = append(match.AllMoves, currentSquareName)
 
 		match.BigCleanup(currentSquareName)
-		err = cfg.showMoves(match, currentSquareName, saveSelected.Name, w, r)
+		err = cfg.showMoves(ctx, w, match, currentGame, currentSquareName, saveSelected.Name)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "show moves error: ", err)
-			return
+			return fmt.Errorf("show moves error: %w", err)
 		}
-		match.MovesSinceLastCapture++
-		cfg.Matches.SetMatch(currentGame, match)
-		noCheck, err := match.HandleIfCheck(w, r, saveSelected)
+		noCheck, err := match.HandleIfCheck(w, multiplier, saveSelected)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+			responses.LogError("couldn't write to page", err)
 		}
 		if noCheck {
 			match.IsWhiteUnderCheck = false
 			match.IsBlackUnderCheck = false
-			cfg.Matches.SetMatch(currentGame, match)
 		}
 		pawnPromotion, err := match.CheckForPawnPromotion(saveSelected.Name, w, userId)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error checking pawn promotion", err)
+			responses.LogError("error checking pawn promotion", err)
 		}
 		if saveSelected.IsPawn && pawnPromotion {
-			return
+			return nil
 		}
-		snapshot := make(map[string]components.Piece, len(match.Pieces))
-		maps.Copy(snapshot, match.Pieces)
-
-		match.PiecesSnapshot = append(match.PiecesSnapshot, snapshot)
 		match.EndTurn(w)
-		cfg.Matches.SetMatch(currentGame, match)
-		return
+		return nil
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return errIllegalMove
 }
 
 func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request) {
 	currentSquareName := r.Header.Get("Hx-Trigger")
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
 	}
-	currentGame := c.Value
-	match, _ := cfg.Matches.GetMatch(currentGame)
+	cmd := cfg.moveCommand(r)
+	cmd.To = currentSquareName
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = cfg.coverCheck(r.Context(), w, match, currentGame, cmd, formMultiplier(r, match, cmd.Player))
+		if err == nil {
+			cfg.playPremoves(r.Context(), match, currentGame)
+		}
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+	respondToMove(w, err)
+}
+
+// coverCheck moves the piece picked up before to cmd.To, one of the squares
+// that gets the king out of check.
+func (cfg *appConfig) coverCheck(ctx context.Context, w io.Writer, match *matches.Match, currentGame string, cmd matches.MoveCommand, multiplier int) error {
+	err := match.Authorize(cmd)
+	if err != nil {
+		return err
+	}
+	defer match.RememberMove(cmd, match.Snapshot())
+
+	currentSquareName := cmd.To
+	userId := cmd.Player
 	currentSquare := match.Board[currentSquareName]
 	selectedSquare := match.SelectedPiece.Tile
 
 	legalMoves := match.CheckLegalMoves()
 
-	userId, _ := cfg.getUserId(r)
-
 	if !slices.Contains(legalMoves, currentSquareName) {
-		w.WriteHeader(http.StatusNoContent)
-		return
+		return errIllegalMove
 	}
 	var check bool
 	var kingCheck bool
@@ -507,8 +556,7 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 		check, _, _ = match.HandleCheckForCheck(currentSquareName, match.SelectedPiece)
 	}
 	if check || kingCheck {
-		w.WriteHeader(http.StatusNoContent)
-		return
+		return errIllegalMove
 	}
 
 	var kingName string
@@ -537,7 +585,7 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 			match.SelectedPiece.Image,
 		)
 
-		err = match.SendMessage(w, message, [2][]int{
+		err := match.SendMessage(w, message, [2][]int{
 			{
 				kingSquare.CoordinatePosition[0],
 				currentSquare.CoordinatePosition[0],
@@ -549,27 +597,24 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 		})
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			return fmt.Errorf("couldn't write to page: %w", err)
 		}
 		saveSelected := match.SelectedPiece
 		match.AllMoves = append(match.AllMoves, currentSquareName)
 
 		match.BigCleanup(currentSquareName)
-		err = cfg.showMoves(match, currentSquareName, saveSelected.Name, w, r)
+		err = cfg.showMoves(ctx, w, match, currentGame, currentSquareName, saveSelected.Name)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "show moves error: ", err)
-			return
+			return fmt.Errorf("show moves error: %w", err)
 		}
 
 		for _, tile := range match.TilesUnderAttack {
 			t := match.Board[tile]
 			if t.Piece.Name != "" {
-				err := responses.RespondWithNewPiece(w, r, t)
+				err := responses.RespondWithNewPiece(w, t, multiplier)
 
 				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "error with new piece", err)
-					return
+					return fmt.Errorf("error with new piece: %w", err)
 				}
 			} else {
 				message := fmt.Sprintf(
@@ -580,8 +625,7 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 				)
 				err = match.SendMessage(w, message, [2][]int{})
 				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "Couldn't write to page", err)
-					return
+					return fmt.Errorf("couldn't write to page: %w", err)
 				}
 
 			}
@@ -589,15 +633,15 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 
 		pawnPromotion, err := match.CheckForPawnPromotion(saveSelected.Name, w, userId)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "check pawn promotion error", err)
+			responses.LogError("check pawn promotion error", err)
 		}
 		if saveSelected.IsPawn && pawnPromotion {
-			return
+			return nil
 		}
 
-		noCheck, err := match.HandleIfCheck(w, r, saveSelected)
+		noCheck, err := match.HandleIfCheck(w, multiplier, saveSelected)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "handle check error", err)
+			responses.LogError("handle check error", err)
 		}
 		if noCheck {
 			match.IsWhiteUnderCheck = false
@@ -605,121 +649,144 @@ func (cfg *appConfig) coverCheckHandler(w http.ResponseWriter, r *http.Request)
 		}
 
 		match.PossibleEnPessant = ""
-		match.MovesSinceLastCapture++
-		cfg.Matches.SetMatch(currentGame, match)
-
-		snapshot := make(map[string]components.Piece, len(match.Pieces))
-		maps.Copy(snapshot, match.Pieces)
-
-		match.PiecesSnapshot = append(match.PiecesSnapshot, snapshot)
 		match.EndTurn(w)
-		cfg.Matches.SetMatch(currentGame, match)
 
-		return
+		return nil
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return errIllegalMove
 }
 
 func (cfg *appConfig) timerHandler(w http.ResponseWriter, r *http.Request) {
 
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
-	} else if strings.Contains(c.Value, "database:") {
+	} else if strings.Contains(currentGame, "database:") {
 		return
 	}
-	currentGame := c.Value
-	match, _ := cfg.Matches.GetMatch(currentGame)
-
-	var toChangeColor string
-	var stayTheSameColor string
-	var toChange int
-	var stayTheSame int
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		var toChangeColor string
+		var stayTheSameColor string
+		var toChange int
+		var stayTheSame int
 
-	if match.IsWhiteTurn {
-		toChangeColor = "white"
-		match.WhiteTimer -= 1
-		toChange = match.WhiteTimer
-		stayTheSame = match.BlackTimer
-		stayTheSameColor = "black"
-	} else {
-		match.BlackTimer -= 1
-		toChangeColor = "black"
-		toChange = match.BlackTimer
-		stayTheSame = match.WhiteTimer
-		stayTheSameColor = "white"
-	}
+		match.TickTimer()
 
-	message := fmt.Sprintf(
-		responses.GetTimerMessage(),
-		toChangeColor,
-		utils.FormatTime(toChange),
-		stayTheSameColor,
-		utils.FormatTime(stayTheSame),
-	)
-
-	err = match.SendMessage(w, message, [2][]int{})
+		if match.IsWhiteTurn {
+			toChangeColor = "white"
+			toChange = match.WhiteTimer
+			stayTheSame = match.BlackTimer
+			stayTheSameColor = "black"
+		} else {
+			toChangeColor = "black"
+			toChange = match.BlackTimer
+			stayTheSame = match.WhiteTimer
+			stayTheSameColor = "white"
+		}
 
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-		return
-	}
+		message := fmt.Sprintf(
+			responses.GetTimerMessage(),
+			toChangeColor,
+			utils.FormatTime(toChange),
+			stayTheSameColor,
+			utils.FormatTime(stayTheSame),
+		)
 
-	cfg.Matches.SetMatch(currentGame, match)
+		err = match.SendMessage(w, message, [2][]int{})
 
-	if match.IsWhiteTurn && (match.WhiteTimer < 0 || match.WhiteTimer == 0) {
-		msg, err := utils.TemplString(components.EndGameModal("0-1", "black", false))
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
 			return
 		}
 
-		err = match.SendMessage(w, msg, [2][]int{})
+		if match.IsWhiteTurn && (match.WhiteTimer < 0 || match.WhiteTimer == 0) {
+			match.PublishGameEnd("0-1", matches.TerminationTimeout)
+			msg, err := utils.TemplString(components.EndGameModal("0-1", "black", false))
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+				return
+			}
 
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
-		}
-	} else if !match.IsWhiteTurn && (match.BlackTimer < 0 || match.BlackTimer == 0) {
-		msg, err := utils.TemplString(components.EndGameModal("1-0", "white", false))
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
-			return
-		}
+			err = match.SendToPlayers(w, msg)
 
-		err = match.SendMessage(w, msg, [2][]int{})
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+				return
+			}
+		} else if !match.IsWhiteTurn && (match.BlackTimer < 0 || match.BlackTimer == 0) {
+			match.PublishGameEnd("1-0", matches.TerminationTimeout)
+			msg, err := utils.TemplString(components.EndGameModal("1-0", "white", false))
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+				return
+			}
 
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			err = match.SendToPlayers(w, msg)
+
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+				return
+			}
 		}
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
 	}
 }
 
 func (cfg *appConfig) handlePromotion(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
+	currentGameName, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
 	}
-	currentGameName := c.Value
-	currentGame, _ := cfg.Matches.GetMatch(currentGameName)
-	pawnName := r.FormValue("pawn")
-	pieceName := r.FormValue("piece")
+	cmd := cfg.moveCommand(r)
+	ok := cfg.Matches.Do(currentGameName, func(currentGame *matches.Match) {
+		multiplier := formMultiplier(r, currentGame, cmd.Player)
+		err = cfg.promote(w, currentGame, currentGameName, cmd, r.FormValue("pawn"), r.FormValue("piece"), multiplier)
+		if err == nil {
+			cfg.playPremoves(r.Context(), currentGame, currentGameName)
+		}
+	})
 
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGameName))
+		return
+	}
+	respondToMove(w, err)
+}
+
+// promote turns the pawn that reached the last rank into the piece the
+// player picked and ends the turn.
+func (cfg *appConfig) promote(w io.Writer, currentGame *matches.Match, currentGameName string, cmd matches.MoveCommand, pawnName, pieceName string, multiplier int) error {
 	allPieces := matches.MakePieces()
 
 	pawnPiece := currentGame.Pieces[pawnName]
 
+	cmd.From = pawnPiece.Tile
+	cmd.To = pawnPiece.Tile
+	cmd.Promotion = pieceName
+	err := currentGame.Authorize(cmd)
+	if err != nil {
+		return err
+	}
+
+	promoted, found := allPieces[pieceName]
+	if !found || promoted.IsKing || promoted.IsPawn || promoted.IsWhite != pawnPiece.IsWhite {
+		return fmt.Errorf("%w: can't promote %v to %v", errInvalidPromotion, pawnName, pieceName)
+	}
+	defer currentGame.RememberMove(cmd, currentGame.Snapshot())
+
 	newPiece := components.Piece{
 		Name:       pawnName,
-		Image:      allPieces[pieceName].Image,
+		Image:      promoted.Image,
 		Tile:       pawnPiece.Tile,
 		IsWhite:    pawnPiece.IsWhite,
-		LegalMoves: allPieces[pieceName].LegalMoves,
-		MovesOnce:  allPieces[pieceName].MovesOnce,
+		LegalMoves: promoted.LegalMoves,
+		MovesOnce:  promoted.MovesOnce,
 		Moved:      true,
 		IsKing:     false,
 		IsPawn:     false,
@@ -731,8 +798,6 @@ func (cfg *appConfig) handlePromotion(w http.ResponseWriter, r *http.Request) {
 	currentSquare.Piece = newPiece
 	currentGame.Board[pawnPiece.Tile] = currentSquare
 
-	cfg.Matches.SetMatch(c.Value, currentGame)
-
 	message := fmt.Sprintf(
 		responses.GetPromotionDoneMessage(),
 		pawnName,
@@ -747,52 +812,45 @@ func (cfg *appConfig) handlePromotion(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-		return
-	}
-
-	userId, err := cfg.isUserLoggedIn(r)
-	if err != nil && !strings.Contains(err.Error(), "named cookie not present") {
-		responses.LogError("user not authorized", err)
+		return fmt.Errorf("couldn't write to page: %w", err)
 	}
 
-	if userId != uuid.Nil {
-		go func(w http.ResponseWriter, r *http.Request) {
-			boardState := make(map[string]string, 0)
-			for k, v := range currentGame.Pieces {
-				boardState[k] = v.Tile
-			}
+	if storesMoves(currentGameName, currentGame) {
+		boardState := make(map[string]string, 0)
+		for k, v := range currentGame.Pieces {
+			boardState[k] = v.Tile
+		}
+		matchId := currentGame.MatchId
 
+		go func() {
 			jsonBoard, err := json.Marshal(boardState)
 
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "error marshaling board state", err)
+				responses.LogError("error marshaling board state", err)
 				return
 			}
 
-			moveDB, err := cfg.database.GetLatestMoveForMatch(r.Context(), currentGame.MatchId)
+			moveDB, err := cfg.database.GetLatestMoveForMatch(context.Background(), matchId)
 
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "database erro", err)
+				responses.LogError("database error", err)
 				return
 			}
 
-			err = cfg.database.UpdateBoardForMove(r.Context(), database.UpdateBoardForMoveParams{
+			err = cfg.database.UpdateBoardForMove(context.Background(), database.UpdateBoardForMoveParams{
 				Board:   jsonBoard,
 				MatchID: moveDB.MatchID,
 				Move:    moveDB.Move,
 			})
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "Couldn't update board for move", err)
-				return
+				responses.LogError("couldn't update board for move", err)
 			}
-		}(w, r)
+		}()
 	}
 
-	noCheck, err := currentGame.HandleIfCheck(w, r, newPiece)
+	noCheck, err := currentGame.HandleIfCheck(w, multiplier, newPiece)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "error with handle check", err)
-		return
+		return fmt.Errorf("error with handle check: %w", err)
 	}
 	if noCheck && (currentGame.IsBlackUnderCheck || currentGame.IsWhiteUnderCheck) {
 		var kingName string
@@ -802,8 +860,7 @@ func (cfg *appConfig) handlePromotion(w http.ResponseWriter, r *http.Request) {
 			kingName = "black_king"
 		} else {
 			currentGame.EndTurn(w)
-			cfg.Matches.SetMatch(currentGameName, currentGame)
-			return
+			return nil
 		}
 
 		currentGame.IsWhiteUnderCheck = false
@@ -827,162 +884,139 @@ func (cfg *appConfig) handlePromotion(w http.ResponseWriter, r *http.Request) {
 		})
 
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			return fmt.Errorf("couldn't write to page: %w", err)
 		}
 	}
 
 	currentGame.PossibleEnPessant = ""
-	currentGame.MovesSinceLastCapture++
-	cfg.Matches.SetMatch(currentGameName, currentGame)
 	currentGame.EndTurn(w)
-	cfg.Matches.SetMatch(currentGameName, currentGame)
+	return nil
 }
 
 func (cfg *appConfig) endGameHandler(w http.ResponseWriter, r *http.Request) {
-	currentGame, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
 	}
 
-	err = r.ParseForm()
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "error parsing form", err)
+	saveGame, found := cfg.Matches.GetMatch(currentGame)
+	if found && saveGame.Result == "" {
+		responses.RespondWithAnError(w, http.StatusConflict, "game is not over", fmt.Errorf("match %v has no result yet", currentGame))
 		return
 	}
 
-	saveGame, _ := cfg.Matches.GetMatch(currentGame.Value)
-	if match, ok := saveGame.IsOnlineMatch(); ok {
-		_ = match.Players["white"].Conn.Close()
-		_ = match.Players["black"].Conn.Close()
-	}
+	if found {
+		if match, ok := saveGame.IsOnlineMatch(); ok {
+			match.Close()
+			if saveGame.Result != "*" {
+				cfg.Rematches.Open(currentGame, saveGame, time.Now())
+			}
+		}
 
-	delete(cfg.Matches.Matches, currentGame.Value)
+		// The result is stored as soon as the game ends, this only waits for
+		// it so the rating change below is already there.
+		err = cfg.recordMatchEnd(r.Context(), saveGame, saveGame.Result, saveGame.Termination)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "error updating match", err)
+			return
+		}
 
-	err = cfg.database.UpdateMatchOnEnd(r.Context(), database.UpdateMatchOnEndParams{
-		Result: r.FormValue("result"),
-		ID:     saveGame.MatchId,
-	})
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "error updating match", err)
-		return
+		cfg.Matches.DeleteMatch(currentGame)
 	}
 
-	cGC := cfg.removeCookie("current_game")
-	http.SetCookie(w, &cGC)
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (cfg *appConfig) surrenderHandler(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
-		return
-	}
-	uC, err := r.Cookie("access_token")
-	currentGame, _ := cfg.Matches.GetMatch(c.Value)
-
-	if err == nil && uC.Value != "" && strings.Contains(c.Value, "online:") {
-		var msg string
-		connection := currentGame.Online
-		userId, err := auth.ValidateJWT(uC.Value, cfg.secret)
+	userId, err := cfg.getUserId(r)
+	if rematch, ok := cfg.Rematches.Get(currentGame, time.Now()); ok && err == nil && rematch.IsPlayer(userId) {
+		err = components.RematchBox(currentGame, false, false, false).Render(r.Context(), w)
 		if err != nil {
-			responses.RespondWithAnError(w, http.StatusUnauthorized, "user not found", err)
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 			return
 		}
-		if connection.Players["white"].ID == userId {
-			msg, err = utils.TemplString(components.EndGameModal("0-1", "black", false))
+		cfg.renderRatingChange(w, r, rematch.MatchId, userId)
+		if _, err := cfg.getAccountId(r); err != nil && rematch.MatchId != 0 {
+			err = components.ClaimGame(rematch.MatchId).Render(r.Context(), w)
 			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
-				return
+				responses.LogError("couldn't render the claim button", err)
 			}
-		} else if connection.Players["black"].ID == userId {
-			msg, err = utils.TemplString(components.EndGameModal("1-0", "white", false))
-			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
-				return
-			}
-		}
-		err = connection.Players["white"].Conn.WriteMessage(websocket.TextMessage, []byte(msg))
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "writing online message error", err)
-			return
-		}
-		err = connection.Players["black"].Conn.WriteMessage(websocket.TextMessage, []byte(msg))
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "writing online message error", err)
-			return
 		}
 		return
 	}
-	if currentGame.IsWhiteTurn {
-		err := components.EndGameModal("0-1", "black", false).Render(r.Context(), w)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error writing the end game modal", err)
-			return
-		}
-	} else {
-		err := components.EndGameModal("1-0", "white", false).Render(r.Context(), w)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error writing the end game modal", err)
-			return
-		}
-	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func (cfg *appConfig) offerDrawHandler(w http.ResponseWriter, r *http.Request) {
-	userId, err := cfg.getUserId(r)
+func (cfg *appConfig) surrenderHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get user id", err)
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
 	}
+	userId, _ := cfg.getUserId(r)
 
-	c, err := r.Cookie("current_game")
-	if err != nil || c.Value == "" {
+	result, online, err := cfg.resign(currentGame, userId)
+	switch {
+	case errors.Is(err, errGameNotFound):
 		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
 		return
+	case errors.Is(err, matches.ErrNotAPlayer):
+		responses.RespondWithAnError(w, http.StatusForbidden, "not a player in this game", err)
+		return
+	case err != nil:
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't resign", err)
+		return
 	}
 
-	currentGame, _ := cfg.Matches.GetMatch(c.Value)
-	onlineGame, found := currentGame.IsOnlineMatch()
-
-	if found {
-		for _, player := range onlineGame.Players {
-			if player.ID == userId {
+	if online {
+		return
+	}
 
-				msg, err := utils.TemplString(components.WaitForDrawModal())
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
-					return
-				}
+	err = components.EndGameModal(result, matches.Winner(result), false).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error writing the end game modal", err)
+		return
+	}
+}
 
-				err = player.Conn.WriteMessage(websocket.TextMessage, []byte(msg))
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "writing online message error", err)
-					return
-				}
+// resign ends the game as lost by userId. In an online game that's the
+// player's own side and both boards get the end game modal, in a local one
+// it's the side on move.
+func (cfg *appConfig) resign(currentGame string, userId uuid.UUID) (result string, online bool, err error) {
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		online = match.IsOnline
+		if !online {
+			result = "1-0"
+			if match.IsWhiteTurn {
+				result = "0-1"
+			}
+			match.PublishGameEnd(result, matches.TerminationResignation)
+			return
+		}
 
-			} else {
-				msg, err := utils.TemplString(components.DrawOfferedModal())
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
-					return
-				}
+		if !match.IsPlayer(userId) {
+			err = matches.ErrNotAPlayer
+			return
+		}
 
-				err = player.Conn.WriteMessage(websocket.TextMessage, []byte(msg))
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "writing online message error", err)
-					return
-				}
-			}
+		result = "1-0"
+		if match.Online.Players["white"].ID == userId {
+			result = "0-1"
+		}
+		msg, renderErr := utils.TemplString(components.EndGameModal(result, matches.Winner(result), false))
+		if renderErr != nil {
+			err = fmt.Errorf("error converting component to string: %w", renderErr)
+			return
 		}
+		match.Online.Hub.Broadcast(msg)
+		match.PublishGameEnd(result, matches.TerminationResignation)
+	})
+	if !ok {
+		return "", false, fmt.Errorf("%w: %v", errGameNotFound, currentGame)
 	}
-}
 
-func (cfg *appConfig) handleCastle(w http.ResponseWriter, currentPiece components.Piece, currentGame string, r *http.Request) error {
-	match, _ := cfg.Matches.GetMatch(currentGame)
+	return result, online, err
+}
 
+func (cfg *appConfig) handleCastle(ctx context.Context, w io.Writer, match *matches.Match, currentGame string, currentPiece components.Piece) error {
 	var king components.Piece
 	var rook components.Piece
 
@@ -1062,26 +1096,91 @@ func (cfg *appConfig) handleCastle(w http.ResponseWriter, currentPiece component
 	match.Board[kTile] = savedKingTile
 	match.Board[rTile] = savedRookTile
 	match.SelectedPiece = components.Piece{}
-	match.IsWhiteTurn = !match.IsWhiteTurn
 	match.PossibleEnPessant = ""
-	match.MovesSinceLastCapture++
-	cfg.Matches.SetMatch(currentGame, match)
 
 	if kingSquare.CoordinatePosition[1]-rookSquare.CoordinatePosition[1] == 1 {
 		match.AllMoves = append(match.AllMoves, "O-O")
-		err := cfg.showMoves(match, "O-O", "king", w, r)
+		err := cfg.showMoves(ctx, w, match, currentGame, "O-O", "king")
 		if err != nil {
 			return err
 		}
 	} else {
 		match.AllMoves = append(match.AllMoves, "O-O-O")
-		err := cfg.showMoves(match, "O-O-O", "king", w, r)
+		err := cfg.showMoves(ctx, w, match, currentGame, "O-O-O", "king")
 		if err != nil {
 			return err
 		}
 	}
 
-	match.GameDone(w)
+	match.EndTurn(w)
 
 	return nil
 }
+
+// moveCommand reads the move the board sends. The player is always the one
+// the request is authenticated as, and a board that doesn't send its ply
+// gets its moves refused as stale.
+func (cfg *appConfig) moveCommand(r *http.Request) matches.MoveCommand {
+	ply, err := strconv.Atoi(r.FormValue("ply"))
+	if err != nil {
+		ply = -1
+	}
+
+	player, _ := cfg.getUserId(r)
+
+	return matches.MoveCommand{
+		Player: player,
+		Ply:    ply,
+		From:   r.FormValue("from"),
+	}
+}
+
+// respondToMove answers a move that couldn't be played. A retry of the move
+// just played, a click with nothing selected or one that doesn't make a
+// move gets no content as if it went through.
+func respondToMove(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, matches.ErrDuplicateMove), errors.Is(err, matches.ErrNotSelected), errors.Is(err, errIllegalMove):
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, matches.ErrStaleMove):
+		responses.RespondWithAnError(w, http.StatusConflict, "stale move", err)
+	case errors.Is(err, errInvalidPromotion):
+		responses.RespondWithAnError(w, http.StatusBadRequest, "invalid promotion", err)
+	case errors.Is(err, matches.ErrMatchOver), errors.Is(err, matches.ErrNotAPlayer), errors.Is(err, matches.ErrNotYourTurn),
+		errors.Is(err, matches.ErrNotYourPiece), errors.Is(err, matches.ErrNoPromotion):
+		responses.RespondWithAnError(w, http.StatusForbidden, "move not allowed", err)
+	default:
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't play the move", err)
+	}
+}
+
+// formMultiplier is the board size the request was sent from, or the
+// player's own when the form doesn't say.
+func formMultiplier(r *http.Request, match *matches.Match, player uuid.UUID) int {
+	multiplier, err := strconv.Atoi(r.FormValue("multiplier"))
+	if err != nil {
+		return playerMultiplier(match, player)
+	}
+
+	return multiplier
+}
+
+// playerMultiplier is the size of the board the player sees.
+func playerMultiplier(match *matches.Match, player uuid.UUID) int {
+	for _, p := range match.Online.Players {
+		if p.ID == player {
+			return max(p.Multiplier, 1)
+		}
+	}
+
+	return max(match.CoordinateMultiplier, 1)
+}
+
+// writeMoveState tells the selecting player's board which piece it moves next.
+func writeMoveState(w io.Writer, match *matches.Match) {
+	_, err := fmt.Fprintf(w, responses.GetMoveStateMessage(), len(match.AllMoves), match.SelectedPiece.Tile)
+	if err != nil {
+		responses.LogError("couldn't write the move state", err)
+	}
+}
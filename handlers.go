@@ -10,7 +10,10 @@ import (
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
 	layout "github.com/NikolaTosic-sudo/chess-live/containers/layouts"
 	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
+	"github.com/NikolaTosic-sudo/chess-live/internal/charts"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
 	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
 	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
@@ -19,26 +22,7 @@ import (
 )
 
 func (cfg *appConfig) boardHandler(w http.ResponseWriter, r *http.Request) {
-	var game string
-	c, err := r.Cookie("current_game")
-
-	if err != nil {
-		if !strings.Contains(err.Error(), "named cookie not present") {
-			responses.LogError("game not found", err)
-		}
-		game = "initial"
-	} else if c.Value != "" {
-		game = c.Value
-	} else {
-		game = "initial"
-	}
-
-	match, ok := cfg.Matches.GetMatch(game)
-
-	if !ok {
-		match = cfg.Matches.GetInitialMatch()
-	}
-
+	match := cfg.Matches.GetInitialMatch()
 	match.FillBoard()
 
 	whitePlayer := components.PlayerStruct{
@@ -54,7 +38,7 @@ func (cfg *appConfig) boardHandler(w http.ResponseWriter, r *http.Request) {
 		Pieces: "black",
 	}
 
-	err = layout.MainPage(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack).Render(r.Context(), w)
+	err := layout.MainPage(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, match.MoveState()).Render(r.Context(), w)
 
 	if err != nil {
 		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
@@ -71,31 +55,7 @@ func (cfg *appConfig) privateBoardHandler(w http.ResponseWriter, r *http.Request
 
 	userName := user.Name
 
-	var game string
-	c, err := r.Cookie("current_game")
-
-	if err != nil {
-		game = "initial"
-	} else if c.Value != "" {
-		game = c.Value
-	} else {
-		game = "initial"
-	}
-
-	sC, err := r.Cookie("saved_game")
-
-	if err == nil {
-		game = sC.Value
-
-		startGame := cfg.makeCookie("current_game", game, "/")
-
-		sGC := cfg.removeCookie("saved_game")
-
-		http.SetCookie(w, &startGame)
-		http.SetCookie(w, &sGC)
-	}
-
-	match, _ := cfg.Matches.GetMatch(game)
+	match := cfg.Matches.GetInitialMatch()
 	match.FillBoard()
 
 	whitePlayer := components.PlayerStruct{
@@ -111,7 +71,7 @@ func (cfg *appConfig) privateBoardHandler(w http.ResponseWriter, r *http.Request
 		Pieces: "black",
 	}
 
-	err = layout.MainPagePrivate(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, false).Render(r.Context(), w)
+	err = layout.MainPagePrivate(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, match.MoveState(), false).Render(r.Context(), w)
 
 	if err != nil {
 		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
@@ -119,161 +79,91 @@ func (cfg *appConfig) privateBoardHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
-func (cfg *appConfig) onlineBoardHandler(w http.ResponseWriter, r *http.Request) {
-	user, err := cfg.getUser(r)
-	if err != nil {
-		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Please try again")
+// gameHandler renders the page of a game in progress. Online games are only
+// played by their two players, anyone else with the link gets to watch.
+func (cfg *appConfig) gameHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame := matches.GameKey(r.PathValue("id"))
+
+	match, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok {
+		responses.RespondWithAnErrorPage(w, r, http.StatusNotFound, "Game not found")
 		return
 	}
 
-	userName := user.Name
-	userId := user.ID
-
-	onlineMatches := cfg.Matches.GetAllOnlineMatches()
-
-	if len(onlineMatches) > 0 {
-		for gameName, match := range onlineMatches {
-
-			game := match.Online
-
-			if game.PlayersQueue.HasSpot() {
-
-				multiplier, err := cfg.getMultiplier(r)
-				if err != nil {
-					responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Please try again")
-					return
-				}
-
-				game.PlayersQueue.Enqueue(components.OnlinePlayerStruct{
-					ID:             userId,
-					Name:           userName,
-					Image:          "/assets/images/user-icon.png",
-					Timer:          utils.FormatTime(600),
-					ReconnectTimer: 30,
-					Multiplier:     multiplier,
-				})
-
-				for color := range game.Players {
-					playerDq, err := game.PlayersQueue.Dequeue()
-
-					if err != nil {
-						responses.RespondWithAnError(w, http.StatusInternalServerError, "", err)
-						return
-					}
-
-					playerDq.Pieces = color
-
-					player := playerDq
-
-					game.Players[color] = player
-				}
-
-				whitePlayer := game.Players["white"]
-				blackPlayer := game.Players["black"]
-
-				matchId, _ := cfg.database.CreateMatch(r.Context(), database.CreateMatchParams{
-					White:    whitePlayer.Name,
-					Black:    blackPlayer.Name,
-					FullTime: 600,
-					IsOnline: true,
-				})
-
-				playersId := []uuid.UUID{whitePlayer.ID, blackPlayer.ID}
-				for _, id := range playersId {
-					_ = cfg.database.CreateMatchUser(r.Context(), database.CreateMatchUserParams{
-						UserID:  id,
-						MatchID: matchId,
-					})
-				}
-
-				startingBoard := matches.MakeBoard()
-				startingPieces := matches.MakePieces()
-
-				match.Board = startingBoard
-				match.Pieces = startingPieces
-				match.SelectedPiece = components.Piece{}
-				match.CoordinateMultiplier = multiplier
-				match.IsWhiteTurn = true
-				match.IsWhiteUnderCheck = false
-				match.IsBlackUnderCheck = false
-				match.WhiteTimer = 600
-				match.BlackTimer = 600
-				match.Addition = 0
-				match.MatchId = matchId
-				match.Online = game
-
-				cfg.Matches.SetMatch(gameName, match)
-
-				startGame := cfg.makeCookie("current_game", gameName, "/")
-
-				match.FillBoard()
-				match.UpdateCoordinates(whitePlayer.Multiplier)
-				http.SetCookie(w, &startGame)
-
-				err = layout.MainPageOnline(match.Board, match.Pieces, whitePlayer.Multiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, false).Render(r.Context(), w)
-				if err != nil {
-					responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
-				}
-				return
-			}
+	if match.IsOnline {
+		userId, err := cfg.getUserId(r)
+		if err != nil || !match.IsPlayer(userId) {
+			http.Redirect(w, r, matches.WatchPath(currentGame), http.StatusSeeOther)
+			return
 		}
-	}
-
-	var currentGame string
 
-	randomString, err := auth.MakeRefreshToken()
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "coudln't make refresh token", err)
+		err = cfg.renderOnlineGame(w, r, currentGame, match, userId, true)
+		if err != nil {
+			responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
+		}
 		return
 	}
-	currentGame = fmt.Sprintf("online:%v", randomString)
-
-	startGame := cfg.makeCookie("current_game", currentGame, "/")
 
-	http.SetCookie(w, &startGame)
-
-	multiplier, err := cfg.getMultiplier(r)
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "error getting multiplier", err)
-		return
+	userName := "Guest"
+	user, err := cfg.getUser(r)
+	loggedIn := err == nil
+	if loggedIn {
+		userName = user.Name
 	}
 
-	qS := queue.PlayersQueue{}
-
-	pQ := qS.NewQueue()
-
-	pQ.Enqueue(components.OnlinePlayerStruct{
-		ID:             userId,
-		Name:           userName,
-		Image:          "/assets/images/user-icon.png",
-		Timer:          utils.FormatTime(600),
-		ReconnectTimer: 30,
-		Multiplier:     multiplier,
-	})
+	match.FillBoard()
 
-	match := matches.Match{
-		IsOnline: true,
-		Online: matches.OnlineGame{
-			Players: map[string]components.OnlinePlayerStruct{
-				"white": {},
-				"black": {},
-			},
-			Message:      make(chan string),
-			PlayerMsg:    make(chan string),
-			Player:       make(chan components.OnlinePlayerStruct),
-			PlayersQueue: pQ,
-		},
+	whitePlayer := components.PlayerStruct{
+		Image:  "/assets/images/user-icon.png",
+		Name:   userName,
+		Timer:  utils.FormatTime(match.WhiteTimer),
+		Pieces: "white",
+	}
+	blackPlayer := components.PlayerStruct{
+		Image:  "/assets/images/user-icon.png",
+		Name:   "Opponent",
+		Timer:  utils.FormatTime(match.BlackTimer),
+		Pieces: "black",
 	}
 
-	cfg.Matches.SetMatch(currentGame, match)
+	err = layout.MainPageLocal(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, match.MoveState(), loggedIn).Render(r.Context(), w)
 
-	err = components.WaitingModal().Render(r.Context(), w)
 	if err != nil {
-		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "couldn't render template")
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
 		return
 	}
 }
 
+// newOnlineGame sets up the connections for an online game whose players
+// haven't been seated yet.
+func (cfg *appConfig) newOnlineGame(currentGame string) matches.OnlineGame {
+	matchHub := hub.New()
+	matchHub.OnConnect(cfg.playerConnected(currentGame))
+	matchHub.OnDisconnect(cfg.playerDisconnected(currentGame))
+	matchHub.OnMessage(cfg.chatMessage(currentGame, chat.ChannelPlayers))
+
+	matchFeed := hub.New()
+	matchFeed.OnConnect(cfg.sendGameFull(currentGame))
+	matchFeed.OnMessage(cfg.feedCommand(currentGame))
+
+	matchSpectators := hub.New()
+	matchSpectators.OnConnect(cfg.spectatorJoined(currentGame))
+	matchSpectators.OnMessage(cfg.chatMessage(currentGame, chat.ChannelSpectators))
+
+	return matches.OnlineGame{
+		Players: map[string]components.OnlinePlayerStruct{
+			"white": {},
+			"black": {},
+		},
+		Hub:                  matchHub,
+		Feed:                 matchFeed,
+		Spectators:           matchSpectators,
+		SpectatorMultipliers: make(map[uuid.UUID]int),
+		Chat:                 chat.NewRoom(),
+		FirstMoveTimeout:     cfg.firstMoveTimeout,
+	}
+}
+
 func (cfg *appConfig) updateMultiplerHandler(w http.ResponseWriter, r *http.Request) {
 
 	err := r.ParseForm()
@@ -290,60 +180,56 @@ func (cfg *appConfig) updateMultiplerHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	c, err := r.Cookie("current_game")
-	var currentGame string
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		currentGame = "initial"
-	} else {
-		currentGame = c.Value
 	}
-	match, _ := cfg.Matches.GetMatch(currentGame)
+	userId, userErr := cfg.getUserId(r)
 
-	match.CoordinateMultiplier = multiplier
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		match.CoordinateMultiplier = multiplier
 
-	onlineGame, found := match.IsOnlineMatch()
+		onlineGame, found := match.IsOnlineMatch()
 
-	if found {
-		userId, err := cfg.getUserId(r)
-
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get user", err)
-			return
-		}
+		if found {
+			if userErr != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get user", userErr)
+				return
+			}
 
-		for color, player := range onlineGame.Players {
-			if player.ID == userId {
-				player.Multiplier = multiplier
-				onlineGame.Players[color] = player
+			for color, player := range onlineGame.Players {
+				if player.ID == userId {
+					player.Multiplier = multiplier
+					onlineGame.Players[color] = player
+				}
 			}
 		}
-	}
 
-	matches.UpdateFilesRanks(w, multiplier)
+		matches.UpdateFilesRanks(w, multiplier)
 
-	match.UpdateCoordinates(multiplier)
-	cfg.Matches.SetMatch(currentGame, match)
+		match.UpdateCoordinates(multiplier)
 
-	multiplierCookie := cfg.makeCookie("multiplier", r.FormValue("multiplier"), "/")
+		multiplierCookie := cfg.makeCookie("multiplier", r.FormValue("multiplier"), "/")
 
-	http.SetCookie(w, &multiplierCookie)
+		http.SetCookie(w, &multiplierCookie)
 
-	for k, piece := range match.Pieces {
-		tile := match.Board[piece.Tile]
+		for k, piece := range match.Pieces {
+			tile := match.Board[piece.Tile]
 
-		_, err := fmt.Fprintf(
-			w,
-			responses.GetSinglePieceMessage(),
-			k,
-			tile.Coordinates[0],
-			tile.Coordinates[1],
-			piece.Image,
-		)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
+			_, err := fmt.Fprintf(
+				w,
+				responses.GetSinglePieceMessage(),
+				k,
+				tile.Coordinates[0],
+				tile.Coordinates[1],
+				piece.Image,
+			)
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+				return
+			}
 		}
-	}
+	})
 }
 
 func (cfg *appConfig) startGameHandler(w http.ResponseWriter, r *http.Request) {
@@ -358,26 +244,30 @@ func (cfg *appConfig) startGameHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		responses.LogError("couldn't parse form", err)
 	}
-	duration := r.FormValue("duration")
-	var newGameName string
+	timer, addition, err := parseDuration(r.FormValue("duration"))
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't convert duration", err)
+		return
+	}
+
 	var matchId int32
 	userName := "Guest"
-	if user != uuid.Nil {
-		newGameName = user.String()
 
+	randomString, err := auth.MakeRefreshToken()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't make the game id", err)
+		return
+	}
+	newGameName := matches.LocalGameKey(randomString)
+
+	if user != uuid.Nil {
 		fullUser, err := cfg.database.GetUserById(r.Context(), user)
 		userName = fullUser.Name
 
 		if err != nil {
 			responses.RespondWithAnError(w, http.StatusNotFound, "user not found in db", err)
 		} else {
-			matchId, err = cfg.database.CreateMatch(r.Context(), database.CreateMatchParams{
-				White:    fullUser.Name,
-				Black:    "Opponent",
-				FullTime: 600,
-				IsOnline: false,
-				Result:   "0-0",
-			})
+			matchId, err = cfg.database.CreateMatch(r.Context(), localMatchParams(fullUser, timer, addition))
 
 			if err != nil {
 				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't create match", err)
@@ -394,34 +284,11 @@ func (cfg *appConfig) startGameHandler(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
-	} else {
-		randomString, err := auth.MakeRefreshToken()
-
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't make refresh token", err)
-			return
-		}
-
-		newGameName = randomString
 	}
 
-	startGame := cfg.makeCookie("current_game", newGameName, "/")
-
 	startingBoard := matches.MakeBoard()
 	startingPieces := matches.MakePieces()
 
-	durationSplit := strings.Split(duration, "+")
-	timer, err := strconv.Atoi(durationSplit[0])
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't convert duration", err)
-		return
-	}
-	addition, err := strconv.Atoi(durationSplit[1])
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't convert duration", err)
-		return
-	}
-
 	multiplier, err := cfg.getMultiplier(r)
 
 	if err != nil {
@@ -439,15 +306,16 @@ func (cfg *appConfig) startGameHandler(w http.ResponseWriter, r *http.Request) {
 		IsBlackUnderCheck:    false,
 		WhiteTimer:           timer,
 		BlackTimer:           timer,
+		TurnStartTimer:       timer,
+		FullTime:             timer,
 		Addition:             addition,
 		MatchId:              matchId,
 	}
 
-	cfg.Matches.SetMatch(newGameName, cur)
-
 	cur.FillBoard()
 	cur.UpdateCoordinates(cur.CoordinateMultiplier)
-	http.SetCookie(w, &startGame)
+	cfg.Matches.SetMatch(newGameName, cur.Clone())
+	w.Header().Set("HX-Push-Url", matches.GamePath(newGameName))
 
 	whitePlayer := components.PlayerStruct{
 		Image:  "/assets/images/user-icon.png",
@@ -471,25 +339,61 @@ func (cfg *appConfig) startGameHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// parseDuration reads a time control like "600+3" into the seconds each side
+// starts with and the seconds added after every move.
+func parseDuration(duration string) (timer, addition int, err error) {
+	full, extra, found := strings.Cut(duration, "+")
+	if !found {
+		return 0, 0, fmt.Errorf("time control %q has no addition", duration)
+	}
+
+	timer, err = strconv.Atoi(full)
+	if err != nil {
+		return 0, 0, err
+	}
+	addition, err = strconv.Atoi(extra)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return timer, addition, nil
+}
+
+// localMatchParams is the stored match of a local game the user starts with
+// the given time control.
+func localMatchParams(user database.User, timer, addition int) database.CreateMatchParams {
+	return database.CreateMatchParams{
+		White:    user.Name,
+		Black:    "Opponent",
+		FullTime: int32(timer),
+		Addition: int32(addition),
+		IsOnline: false,
+		Result:   "0-0",
+		Variant:  queue.VariantStandard,
+		StartFen: matches.StartFEN,
+		WhiteID:  uuid.NullUUID{UUID: user.ID, Valid: true},
+	}
+}
+
 func (cfg *appConfig) resumeGameHandler(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNoContent, "no game found", err)
 		return
 	}
 
-	match, ok := cfg.Matches.GetMatch(c.Value)
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		match.FillBoard()
+		match.UpdateCoordinates(match.CoordinateMultiplier)
+	})
 
 	if !ok {
 		responses.RespondWithAnError(w, http.StatusNoContent, "no game found", err)
 		return
 	}
 
-	match.FillBoard()
-	match.UpdateCoordinates(match.CoordinateMultiplier)
-
-	err = components.StartGameRight().Render(r.Context(), w)
+	err = components.StartGameRight(false).Render(r.Context(), w)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 		return
@@ -497,42 +401,41 @@ func (cfg *appConfig) resumeGameHandler(w http.ResponseWriter, r *http.Request)
 }
 
 func (cfg *appConfig) getAllMovesHandler(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNoContent, "no game found", err)
 		return
 	}
 
-	match, ok := cfg.Matches.GetMatch(c.Value)
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		for i := 1; i <= len(match.AllMoves); i++ {
+			var message string
+			if i%2 == 0 {
+				message = fmt.Sprintf(
+					responses.GetMovesUpdateMessage(),
+					match.AllMoves[i-1],
+				)
+			} else {
+				message = fmt.Sprintf(
+					responses.GetMovesNumberUpdateMessage(),
+					i/2+1,
+					match.AllMoves[i-1],
+				)
+			}
+
+			_, err := fmt.Fprint(w, message)
+			if err != nil {
+				responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+				return
+			}
+		}
+	})
 
 	if !ok {
 		responses.RespondWithAnError(w, http.StatusNoContent, "no game found", err)
 		return
 	}
-
-	for i := 1; i <= len(match.AllMoves); i++ {
-		var message string
-		if i%2 == 0 {
-			message = fmt.Sprintf(
-				responses.GetMovesUpdateMessage(),
-				match.AllMoves[i-1],
-			)
-		} else {
-			message = fmt.Sprintf(
-				responses.GetMovesNumberUpdateMessage(),
-				i/2+1,
-				match.AllMoves[i-1],
-			)
-		}
-
-		err := match.SendMessage(w, message, [2][]int{})
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
-			return
-		}
-
-	}
 }
 
 func (cfg *appConfig) timeOptionHandler(w http.ResponseWriter, r *http.Request) {
@@ -625,14 +528,21 @@ func (cfg *appConfig) matchHistoryHandler(w http.ResponseWriter, r *http.Request
 			return
 		}
 		newMatch := components.MatchStruct{
-			White:   dbMatches[i].White,
-			Black:   dbMatches[i].Black,
-			Ended:   dbMatches[i].Ended,
-			Date:    dbMatches[i].CreatedAt.Format("Jan 2, 2006"),
-			NoMoves: int(numberOfMoves),
-			Result:  dbMatches[i].Result,
-			Online:  dbMatches[i].IsOnline,
-			MatchId: int(dbMatches[i].ID),
+			White:       dbMatches[i].White,
+			Black:       dbMatches[i].Black,
+			Ended:       dbMatches[i].Ended,
+			Date:        dbMatches[i].CreatedAt.Format("Jan 2, 2006"),
+			NoMoves:     int(numberOfMoves),
+			Result:      dbMatches[i].Result,
+			Online:      dbMatches[i].IsOnline,
+			MatchId:     int(dbMatches[i].ID),
+			Termination: dbMatches[i].Termination,
+			TimeControl: timeControl(int(dbMatches[i].FullTime), int(dbMatches[i].Addition)),
+			Variant:     dbMatches[i].Variant,
+			Rated:       dbMatches[i].Rated,
+		}
+		if dbMatches[i].EndedAt.Valid {
+			newMatch.EndedAt = dbMatches[i].EndedAt.Time.Format("Jan 2, 15:04")
 		}
 
 		matches = append(matches, newMatch)
@@ -672,36 +582,7 @@ func (cfg *appConfig) playHandler(w http.ResponseWriter, r *http.Request) {
 		userName = "Guest"
 	}
 
-	var game string
-	c, err := r.Cookie("current_game")
-
-	if err != nil {
-		game = "initial"
-	} else if strings.Contains(c.Value, "database:") {
-		cGC := cfg.removeCookie("current_game")
-
-		http.SetCookie(w, &cGC)
-		game = "initial"
-	} else if c.Value != "" {
-		game = c.Value
-	} else {
-		game = "initial"
-	}
-
-	sC, err := r.Cookie("saved_game")
-
-	if err == nil {
-		game = sC.Value
-
-		startGame := cfg.makeCookie("current_game", game, "/")
-
-		sGC := cfg.removeCookie("saved_game")
-
-		http.SetCookie(w, &startGame)
-		http.SetCookie(w, &sGC)
-	}
-
-	match, _ := cfg.Matches.GetMatch(game)
+	match := cfg.Matches.GetInitialMatch()
 	match.FillBoard()
 
 	whitePlayer := components.PlayerStruct{
@@ -717,7 +598,7 @@ func (cfg *appConfig) playHandler(w http.ResponseWriter, r *http.Request) {
 		Pieces: "black",
 	}
 
-	err = layout.MainPagePrivate(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, false).Render(r.Context(), w)
+	err = layout.MainPagePrivate(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, match.MoveState(), false).Render(r.Context(), w)
 
 	if err != nil {
 		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
@@ -741,16 +622,22 @@ func (cfg *appConfig) matchesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newGame := fmt.Sprintf("database:matchId-%v", match.ID)
-
-	c, noCookie := r.Cookie("current_game")
-
-	if noCookie == nil && c.Value != "" {
-		saveGame := cfg.makeCookie("saved_game", c.Value, "/")
+	requested := r.URL.Query().Get("replay")
+	replayId, err := cfg.replayId(strId, requested)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't make the replay id", err)
+		return
+	}
 
-		http.SetCookie(w, &saveGame)
+	// A page loaded without htmx only gets its replay id into its address by
+	// going there.
+	if replayId != requested && r.Header.Get("HX-Request") == "" {
+		http.Redirect(w, r, matches.ReplayPath(strId, replayId), http.StatusSeeOther)
+		return
 	}
 
+	newGame := matches.ReplayKey(strId, replayId)
+
 	multiplier, err := cfg.getMultiplier(r)
 
 	if err != nil {
@@ -758,8 +645,6 @@ func (cfg *appConfig) matchesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	startGame := cfg.makeCookie("current_game", newGame, "/")
-
 	startingBoard := matches.MakeBoard()
 	startingPieces := matches.MakePieces()
 
@@ -773,14 +658,14 @@ func (cfg *appConfig) matchesHandler(w http.ResponseWriter, r *http.Request) {
 		IsBlackUnderCheck:    false,
 		WhiteTimer:           int(match.FullTime),
 		BlackTimer:           int(match.FullTime),
+		TurnStartTimer:       int(match.FullTime),
 		MatchId:              match.ID,
 	}
 
-	cfg.Matches.SetMatch(newGame, cur)
-
 	cur.FillBoard()
 	cur.UpdateCoordinates(cur.CoordinateMultiplier)
-	http.SetCookie(w, &startGame)
+	cfg.Matches.SetMatch(newGame, cur.Clone())
+	w.Header().Set("HX-Push-Url", matches.ReplayPath(strId, replayId))
 
 	whitePlayer := components.PlayerStruct{
 		Image:  "/assets/images/user-icon.png",
@@ -795,40 +680,79 @@ func (cfg *appConfig) matchesHandler(w http.ResponseWriter, r *http.Request) {
 		Pieces: "black",
 	}
 
-	moves, err := cfg.database.GetAllMovesForMatch(r.Context(), match.ID)
+	dbMoves, err := cfg.database.GetMoveTimesForMatch(r.Context(), match.ID)
 
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get all moves", err)
 		return
 	}
 
-	err = layout.MatchHistoryBoard(cur.Board, cur.Pieces, cur.CoordinateMultiplier, whitePlayer, blackPlayer, cur.TakenPiecesWhite, cur.TakenPiecesBlack, moves).Render(r.Context(), w)
+	var moves []components.MoveTimeStruct
+	var spent []int
+	for _, move := range dbMoves {
+		moves = append(moves, components.MoveTimeStruct{
+			Move:      move.Move,
+			TimeSpent: int(move.TimeSpent),
+			DrawOffer: move.DrawOffer,
+		})
+		spent = append(spent, int(move.TimeSpent))
+	}
+
+	chart := charts.TimeUsage(spent, 240, 120)
+
+	dbChat, err := cfg.database.GetChatMessagesForMatch(r.Context(), match.ID)
+
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't get chat messages", err)
+		return
+	}
+
+	var chatMessages []components.ChatMessageStruct
+	for _, message := range dbChat {
+		chatMessages = append(chatMessages, components.ChatMessageStruct{
+			UserID:  message.UserID,
+			Name:    message.Name,
+			Text:    message.Body,
+			Channel: message.Channel,
+		})
+	}
+
+	err = layout.MatchHistoryBoard(cur.Board, cur.Pieces, cur.CoordinateMultiplier, whitePlayer, blackPlayer, cur.TakenPiecesWhite, cur.TakenPiecesBlack, moves, chart, chatMessages).Render(r.Context(), w)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 		return
 	}
 }
 
+// replayId is the id of a new replay of the match. The one the page asks for
+// is kept, unless another replay is already using it.
+func (cfg *appConfig) replayId(matchId, requested string) (string, error) {
+	if requested != "" {
+		if _, taken := cfg.Matches.GetMatch(matches.ReplayKey(matchId, requested)); !taken {
+			return requested, nil
+		}
+	}
+
+	return auth.MakeRefreshToken()
+}
+
 func (cfg *appConfig) moveHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	tile := r.PathValue("tile")
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", err)
 		return
 	}
 
-	cookie := strings.Split(c.Value, "-")
-
-	matchId, err := strconv.Atoi(cookie[1])
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't convert value", err)
+	replay, found := cfg.Matches.GetMatch(currentGame)
+	if !found || !strings.HasPrefix(currentGame, "database:") {
+		responses.RespondWithAnError(w, http.StatusNotFound, "no game found", fmt.Errorf("%v isn't a replay", currentGame))
 		return
 	}
 
 	board, err := cfg.database.GetBoardForMove(r.Context(), database.GetBoardForMoveParams{
-		MatchID: int32(matchId),
+		MatchID: replay.MatchId,
 		Move:    tile,
 	})
 
@@ -855,15 +779,15 @@ func (cfg *appConfig) moveHistoryHandler(w http.ResponseWriter, r *http.Request)
 		curr.Tile = v
 		pieces[k] = curr
 	}
-	curr, _ := cfg.Matches.GetMatch(c.Value)
+	cfg.Matches.Do(currentGame, func(curr *matches.Match) {
+		curr.CleanFillBoard(pieces)
 
-	curr.CleanFillBoard(pieces)
-
-	err = components.UpdateBoardHistory(curr.Board, pieces, curr.CoordinateMultiplier, utils.FormatTime(int(board.WhiteTime)), utils.FormatTime(int(board.BlackTime))).Render(r.Context(), w)
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
-		return
-	}
+		err = components.UpdateBoardHistory(curr.Board, pieces, curr.CoordinateMultiplier, utils.FormatTime(int(board.WhiteTime)), utils.FormatTime(int(board.BlackTime))).Render(r.Context(), w)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+			return
+		}
+	})
 }
 
 func (cfg *appConfig) endModalHandler(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/google/uuid"
+)
+
+func TestLocalMatchTimeControl(t *testing.T) {
+	user := database.User{ID: uuid.New(), Name: "magnus"}
+
+	tests := []struct {
+		name         string
+		duration     string
+		wantFullTime int32
+		wantAddition int32
+		wantErr      bool
+	}{
+		{
+			name:         "Default time control",
+			duration:     "600+0",
+			wantFullTime: 600,
+			wantAddition: 0,
+		},
+		{
+			name:         "Blitz with increment",
+			duration:     "300+5",
+			wantFullTime: 300,
+			wantAddition: 5,
+		},
+		{
+			name:     "No addition",
+			duration: "300",
+			wantErr:  true,
+		},
+		{
+			name:     "Not a number",
+			duration: "five+0",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer, addition, err := parseDuration(tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration(%q) error = %v, wantErr %v", tt.duration, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			params := localMatchParams(user, timer, addition)
+			if params.FullTime != tt.wantFullTime || params.Addition != tt.wantAddition {
+				t.Errorf("stored time control = %v+%v, want %v+%v", params.FullTime, params.Addition, tt.wantFullTime, tt.wantAddition)
+			}
+			if !params.WhiteID.Valid || params.WhiteID.UUID != user.ID {
+				t.Errorf("stored white player = %v, want %v", params.WhiteID, user.ID)
+			}
+		})
+	}
+}
+
+func TestConcurrentReplaysOfTheSameMatch(t *testing.T) {
+	cfg := &appConfig{Matches: matches.NewMatches()}
+	matchId := "7"
+
+	replay := func(requested string) string {
+		t.Helper()
+
+		replayId, err := cfg.replayId(matchId, requested)
+		if err != nil {
+			t.Fatalf("replayId() error = %v", err)
+		}
+
+		match := matches.Match{
+			Board:       matches.MakeBoard(),
+			Pieces:      matches.MakePieces(),
+			IsWhiteTurn: true,
+			MatchId:     7,
+		}
+		match.FillBoard()
+		cfg.Matches.SetMatch(matches.ReplayKey(matchId, replayId), match)
+
+		return replayId
+	}
+
+	first := replay("")
+	// A second tab opened on the first one's address gets a replay of its own.
+	second := replay(first)
+	if first == second {
+		t.Fatalf("both replays got the id %v", first)
+	}
+
+	pageGame := func(replayId string) string {
+		t.Helper()
+
+		r := httptest.NewRequest("GET", "/move-history/e4", nil)
+		r.Header.Set("HX-Current-URL", "http://localhost"+matches.ReplayPath(matchId, replayId))
+		currentGame, err := cfg.currentGame(r)
+		if err != nil {
+			t.Fatalf("currentGame() error = %v", err)
+		}
+
+		return currentGame
+	}
+
+	firstGame := pageGame(first)
+	secondGame := pageGame(second)
+	if firstGame == secondGame {
+		t.Fatalf("both pages step through %v", firstGame)
+	}
+
+	// Stepping to a move in the first tab leaves the second one where it is.
+	cfg.Matches.Do(firstGame, func(match *matches.Match) {
+		pieces := matches.MakePieces()
+		pawn := pieces["white_pawn_5"]
+		pawn.Tile = "4e"
+		pieces["white_pawn_5"] = pawn
+		match.CleanFillBoard(pieces)
+	})
+
+	got, _ := cfg.Matches.GetMatch(firstGame)
+	if got.Board["4e"].Piece.Name == "" {
+		t.Fatalf("the first replay didn't move to e4")
+	}
+	other, _ := cfg.Matches.GetMatch(secondGame)
+	if name := other.Board["4e"].Piece.Name; name != "" {
+		t.Errorf("second replay has %v on e4, want the starting position", name)
+	}
+}
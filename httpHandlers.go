@@ -140,18 +140,53 @@ func (cfg *appConfig) registerAllHandlers() {
 		},
 		{
 			method:     "GET",
-			reqPath:    "/online",
+			reqPath:    "/game/{id}",
+			handleFunc: cfg.gameHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/game/{id}/ws",
 			handleFunc: cfg.wsHandler,
 		},
 		{
 			method:     "GET",
-			reqPath:    "/play-online",
-			handleFunc: cfg.middleWareCheckForUserPrivate(cfg.onlineBoardHandler),
+			reqPath:    "/api/feed",
+			handleFunc: cfg.feedHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/claim-modal",
+			handleFunc: cfg.claimModalHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/lobby",
+			handleFunc: cfg.lobbyHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/lobby/seeks",
+			handleFunc: cfg.createSeekHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/lobby/seeks/{id}/accept",
+			handleFunc: cfg.acceptSeekHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/lobby/seeks/{id}/cancel",
+			handleFunc: cfg.cancelSeekHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/seek/ws",
+			handleFunc: cfg.seekWsHandler,
 		},
 		{
 			method:     "GET",
-			reqPath:    "/searching",
-			handleFunc: cfg.searchingOppHandler,
+			reqPath:    "/seek/join",
+			handleFunc: cfg.joinSeekHandler,
 		},
 		{
 			method:     "GET",
@@ -163,6 +198,16 @@ func (cfg *appConfig) registerAllHandlers() {
 			reqPath:    "/surrender",
 			handleFunc: cfg.surrenderHandler,
 		},
+		{
+			method:     "GET",
+			reqPath:    "/cancel-premoves",
+			handleFunc: cfg.cancelPremovesHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/abort",
+			handleFunc: cfg.abortHandler,
+		},
 		{
 			method:     "GET",
 			reqPath:    "/offer-draw",
@@ -178,6 +223,26 @@ func (cfg *appConfig) registerAllHandlers() {
 			reqPath:    "/accept-draw",
 			handleFunc: cfg.accpetDrawHandler,
 		},
+		{
+			method:     "GET",
+			reqPath:    "/claim-draw",
+			handleFunc: cfg.claimDrawHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/takeback",
+			handleFunc: cfg.takebackHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/decline-takeback",
+			handleFunc: cfg.declineTakebackHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/accept-takeback",
+			handleFunc: cfg.acceptTakebackHandler,
+		},
 		{
 			method:     "GET",
 			reqPath:    "/wait-reconnect",
@@ -195,18 +260,73 @@ func (cfg *appConfig) registerAllHandlers() {
 		},
 		{
 			method:     "GET",
-			reqPath:    "/continue-online",
-			handleFunc: cfg.continueOnlineHandler,
+			reqPath:    "/handle-end",
+			handleFunc: cfg.endModalHandler,
 		},
 		{
 			method:     "GET",
-			reqPath:    "/handle-end",
-			handleFunc: cfg.endModalHandler,
+			reqPath:    "/rematch",
+			handleFunc: cfg.rematchStatusHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/rematch",
+			handleFunc: cfg.rematchHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/rematch/decline",
+			handleFunc: cfg.declineRematchHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/challenges",
+			handleFunc: cfg.challengesHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/challenges",
+			handleFunc: cfg.createChallengeHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/challenge/{id}",
+			handleFunc: cfg.inviteHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/challenge/{id}/accept",
+			handleFunc: cfg.acceptChallengeHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/challenge/{id}/decline",
+			handleFunc: cfg.declineChallengeHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/challenge/{id}/cancel",
+			handleFunc: cfg.cancelChallengeHandler,
 		},
 		{
 			method:     "GET",
-			reqPath:    "/cancel-online-search",
-			handleFunc: cfg.cancelOnlineSearchHandler,
+			reqPath:    "/watch/{id}",
+			handleFunc: cfg.watchHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/watch/{id}/ws",
+			handleFunc: cfg.watchWsHandler,
+		},
+		{
+			method:     "GET",
+			reqPath:    "/watch/{id}/chat",
+			handleFunc: cfg.chatHistoryHandler,
+		},
+		{
+			method:     "POST",
+			reqPath:    "/watch/{id}/mute",
+			handleFunc: cfg.muteHandler,
 		},
 	}
 
@@ -12,7 +12,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-const TokenTypeAccess = "chess-access"
+const (
+	TokenTypeAccess = "chess-access"
+	TokenTypeGuest  = "chess-guest"
+
+	// GuestTokenDuration is how long a guest keeps the same identity, long
+	// enough to finish a game and claim it afterwards.
+	GuestTokenDuration = 7 * 24 * time.Hour
+)
 
 func HashedPassword(password string) (string, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -31,17 +38,35 @@ func MakeJWT(
 	userID uuid.UUID,
 	tokenSecret string,
 ) (string, error) {
+	return makeToken(userID, tokenSecret, TokenTypeAccess, time.Hour)
+}
+
+// MakeGuestJWT signs the id of a player without an account. It is only
+// accepted by ValidateGuestJWT, never as an access token.
+func MakeGuestJWT(guestID uuid.UUID, tokenSecret string) (string, error) {
+	return makeToken(guestID, tokenSecret, TokenTypeGuest, GuestTokenDuration)
+}
+
+func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	return validateToken(tokenString, tokenSecret, TokenTypeAccess)
+}
+
+func ValidateGuestJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	return validateToken(tokenString, tokenSecret, TokenTypeGuest)
+}
+
+func makeToken(id uuid.UUID, tokenSecret, issuer string, expiresIn time.Duration) (string, error) {
 	signingKey := []byte(tokenSecret)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
+		Issuer:    issuer,
 		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(time.Hour)),
-		Subject:   userID.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+		Subject:   id.String(),
 	})
 	return token.SignedString(signingKey)
 }
 
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+func validateToken(tokenString, tokenSecret, wantIssuer string) (uuid.UUID, error) {
 	claimsStruct := jwt.RegisteredClaims{}
 	token, err := jwt.ParseWithClaims(
 		tokenString,
@@ -61,7 +86,7 @@ func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
 	if err != nil {
 		return uuid.Nil, err
 	}
-	if issuer != string(TokenTypeAccess) {
+	if issuer != wantIssuer {
 		return uuid.Nil, errors.New("invalid issuer")
 	}
 
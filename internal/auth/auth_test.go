@@ -63,6 +63,7 @@ func TestCheckPasswordHash(t *testing.T) {
 func TestValidateJWT(t *testing.T) {
 	userID := uuid.New()
 	validToken, _ := MakeJWT(userID, "secret")
+	guestToken, _ := MakeGuestJWT(userID, "secret")
 
 	tests := []struct {
 		name        string
@@ -92,6 +93,13 @@ func TestValidateJWT(t *testing.T) {
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
+		{
+			name:        "Guest token",
+			tokenString: guestToken,
+			tokenSecret: "secret",
+			wantUserID:  uuid.Nil,
+			wantErr:     true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,3 +115,49 @@ func TestValidateJWT(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGuestJWT(t *testing.T) {
+	guestID := uuid.New()
+	guestToken, _ := MakeGuestJWT(guestID, "secret")
+	accessToken, _ := MakeJWT(guestID, "secret")
+
+	tests := []struct {
+		name        string
+		tokenString string
+		tokenSecret string
+		wantGuestID uuid.UUID
+		wantErr     bool
+	}{
+		{
+			name:        "Valid token",
+			tokenString: guestToken,
+			tokenSecret: "secret",
+			wantGuestID: guestID,
+		},
+		{
+			name:        "Wrong secret",
+			tokenString: guestToken,
+			tokenSecret: "wrong_secret",
+			wantErr:     true,
+		},
+		{
+			name:        "Access token",
+			tokenString: accessToken,
+			tokenSecret: "secret",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotGuestID, err := ValidateGuestJWT(tt.tokenString, tt.tokenSecret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGuestJWT() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotGuestID != tt.wantGuestID {
+				t.Errorf("ValidateGuestJWT() gotGuestID = %v, want %v", gotGuestID, tt.wantGuestID)
+			}
+		})
+	}
+}
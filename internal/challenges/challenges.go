@@ -0,0 +1,279 @@
+package challenges
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+const (
+	ColorWhite  = "white"
+	ColorBlack  = "black"
+	ColorRandom = "random"
+)
+
+// Time controls a challenge can be made with, in minutes and seconds added
+// per move.
+var (
+	Minutes   = []int{1, 3, 5, 10, 15, 30}
+	Additions = []int{0, 1, 2, 3, 5, 10}
+)
+
+var (
+	ErrNotFound       = errors.New("challenge not found or expired")
+	ErrNotAllowed     = errors.New("this challenge isn't for you")
+	ErrOwnChallenge   = errors.New("you can't accept your own challenge")
+	ErrInvalidTime    = errors.New("invalid time control")
+	ErrInvalidColor   = errors.New("invalid color")
+	ErrChallengeSelf  = errors.New("you can't challenge yourself")
+	ErrAlreadyStarted = errors.New("challenge was already accepted")
+)
+
+// Challenge is an offer to play one online game. A challenge without a
+// recipient is an invite link that anyone who has the link can accept, once.
+type Challenge struct {
+	ID        string
+	From      components.OnlinePlayerStruct
+	To        uuid.UUID
+	ToName    string
+	FullTime  int
+	Addition  int
+	Color     string
+	NewGame   string
+	ExpiresAt time.Time
+	accepted  bool
+}
+
+func (c Challenge) IsInvite() bool {
+	return c.To == uuid.Nil
+}
+
+// Seats puts the challenger and the player accepting on their colors.
+// whiteFirst decides who plays white when the challenger asked for random.
+func (c Challenge) Seats(opponent components.OnlinePlayerStruct, whiteFirst bool) (white, black components.OnlinePlayerStruct) {
+	challengerWhite := c.Color == ColorWhite || c.Color == ColorRandom && whiteFirst
+	if challengerWhite {
+		white, black = c.From, opponent
+	} else {
+		white, black = opponent, c.From
+	}
+
+	white.Pieces = ColorWhite
+	black.Pieces = ColorBlack
+
+	return white, black
+}
+
+func Validate(minutes, addition int, color string) error {
+	if !slices.Contains(Minutes, minutes) || !slices.Contains(Additions, addition) {
+		return ErrInvalidTime
+	}
+
+	if color != ColorWhite && color != ColorBlack && color != ColorRandom {
+		return ErrInvalidColor
+	}
+
+	return nil
+}
+
+// Store keeps pending challenges in memory until they're accepted, declined,
+// canceled or expire.
+type Store struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	challenges map[string]*Challenge
+}
+
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:        ttl,
+		challenges: make(map[string]*Challenge),
+	}
+}
+
+func (s *Store) Create(c Challenge, now time.Time) (Challenge, error) {
+	if c.From.ID == c.To {
+		return Challenge{}, ErrChallengeSelf
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return Challenge{}, err
+	}
+
+	c.ID = hex.EncodeToString(token)
+	c.ExpiresAt = now.Add(s.ttl)
+	c.NewGame = ""
+	c.accepted = false
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+	s.challenges[c.ID] = &c
+
+	return c, nil
+}
+
+func (s *Store) Get(id string, now time.Time) (Challenge, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.get(id, now)
+	if !ok {
+		return Challenge{}, false
+	}
+
+	return *c, true
+}
+
+// ForUser lists the pending challenges sent to and made by the user, oldest
+// first.
+func (s *Store) ForUser(userId uuid.UUID, now time.Time) (incoming, outgoing []Challenge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	for _, c := range s.challenges {
+		if c.accepted {
+			continue
+		}
+		if c.To == userId {
+			incoming = append(incoming, *c)
+		}
+		if c.From.ID == userId {
+			outgoing = append(outgoing, *c)
+		}
+	}
+
+	byExpiry := func(a, b Challenge) int {
+		return a.ExpiresAt.Compare(b.ExpiresAt)
+	}
+	slices.SortFunc(incoming, byExpiry)
+	slices.SortFunc(outgoing, byExpiry)
+
+	return incoming, outgoing
+}
+
+// Accept claims the challenge for the user. Only one accept can succeed, and
+// the caller reports the game it started with Started.
+func (s *Store) Accept(id string, userId uuid.UUID, now time.Time) (Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.get(id, now)
+	if !ok {
+		return Challenge{}, ErrNotFound
+	}
+
+	if c.accepted {
+		return Challenge{}, ErrAlreadyStarted
+	}
+
+	if c.From.ID == userId {
+		return Challenge{}, ErrOwnChallenge
+	}
+
+	if !c.IsInvite() && c.To != userId {
+		return Challenge{}, ErrNotAllowed
+	}
+
+	c.accepted = true
+
+	return *c, nil
+}
+
+// Started records the game an accepted challenge is played in, so the
+// challenger can be moved into it.
+func (s *Store) Started(id, newGame string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.challenges[id]; ok {
+		c.NewGame = newGame
+		c.ExpiresAt = now.Add(s.ttl)
+	}
+}
+
+// TakeStarted returns a game started from one of the user's challenges and
+// forgets the challenge.
+func (s *Store) TakeStarted(userId uuid.UUID, now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	for id, c := range s.challenges {
+		if c.From.ID == userId && c.NewGame != "" {
+			delete(s.challenges, id)
+			return c.NewGame, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *Store) Decline(id string, userId uuid.UUID, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.get(id, now)
+	if !ok || c.accepted {
+		return ErrNotFound
+	}
+
+	if c.From.ID == userId || !c.IsInvite() && c.To != userId {
+		return ErrNotAllowed
+	}
+
+	delete(s.challenges, id)
+
+	return nil
+}
+
+func (s *Store) Cancel(id string, userId uuid.UUID, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.get(id, now)
+	if !ok || c.accepted {
+		return ErrNotFound
+	}
+
+	if c.From.ID != userId {
+		return ErrNotAllowed
+	}
+
+	delete(s.challenges, id)
+
+	return nil
+}
+
+func (s *Store) get(id string, now time.Time) (*Challenge, bool) {
+	c, ok := s.challenges[id]
+	if !ok {
+		return nil, false
+	}
+
+	if now.After(c.ExpiresAt) {
+		delete(s.challenges, id)
+		return nil, false
+	}
+
+	return c, true
+}
+
+func (s *Store) sweep(now time.Time) {
+	for id, c := range s.challenges {
+		if now.After(c.ExpiresAt) {
+			delete(s.challenges, id)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package challenges
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		minutes  int
+		addition int
+		color    string
+		wantErr  error
+	}{
+		{
+			name:    "Ten minutes as white",
+			minutes: 10,
+			color:   ColorWhite,
+		},
+		{
+			name:     "Three plus two at random",
+			minutes:  3,
+			addition: 2,
+			color:    ColorRandom,
+		},
+		{
+			name:    "Unsupported minutes",
+			minutes: 7,
+			color:   ColorBlack,
+			wantErr: ErrInvalidTime,
+		},
+		{
+			name:     "Unsupported addition",
+			minutes:  10,
+			addition: 4,
+			color:    ColorBlack,
+			wantErr:  ErrInvalidTime,
+		},
+		{
+			name:    "Unknown color",
+			minutes: 10,
+			color:   "green",
+			wantErr: ErrInvalidColor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.minutes, tt.addition, tt.color); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSeats(t *testing.T) {
+	challenger := components.OnlinePlayerStruct{ID: uuid.New(), Name: "challenger"}
+	opponent := components.OnlinePlayerStruct{ID: uuid.New(), Name: "opponent"}
+
+	tests := []struct {
+		name       string
+		color      string
+		whiteFirst bool
+		wantWhite  string
+	}{
+		{
+			name:      "Challenger picked white",
+			color:     ColorWhite,
+			wantWhite: "challenger",
+		},
+		{
+			name:       "Challenger picked black",
+			color:      ColorBlack,
+			whiteFirst: true,
+			wantWhite:  "opponent",
+		},
+		{
+			name:       "Random gave the challenger white",
+			color:      ColorRandom,
+			whiteFirst: true,
+			wantWhite:  "challenger",
+		},
+		{
+			name:      "Random gave the challenger black",
+			color:     ColorRandom,
+			wantWhite: "opponent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Challenge{From: challenger, Color: tt.color}
+			white, black := c.Seats(opponent, tt.whiteFirst)
+
+			if white.Name != tt.wantWhite {
+				t.Errorf("white = %v, want %v", white.Name, tt.wantWhite)
+			}
+			if white.Pieces != ColorWhite || black.Pieces != ColorBlack {
+				t.Errorf("pieces = %v/%v, want white/black", white.Pieces, black.Pieces)
+			}
+			if white.ID == black.ID {
+				t.Errorf("the same player got both colors")
+			}
+		})
+	}
+}
+
+func TestAccept(t *testing.T) {
+	challenger := uuid.New()
+	recipient := uuid.New()
+	stranger := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		to       uuid.UUID
+		act      func(s *Store, id string)
+		acceptBy uuid.UUID
+		acceptAt time.Time
+		wantErr  error
+	}{
+		{
+			name:     "Recipient accepts",
+			to:       recipient,
+			acceptBy: recipient,
+			acceptAt: now,
+		},
+		{
+			name:     "Someone else can't accept a direct challenge",
+			to:       recipient,
+			acceptBy: stranger,
+			acceptAt: now,
+			wantErr:  ErrNotAllowed,
+		},
+		{
+			name:     "Anyone with the link accepts an invite",
+			to:       uuid.Nil,
+			acceptBy: stranger,
+			acceptAt: now,
+		},
+		{
+			name:     "Challenger can't accept",
+			to:       uuid.Nil,
+			acceptBy: challenger,
+			acceptAt: now,
+			wantErr:  ErrOwnChallenge,
+		},
+		{
+			name:     "Invite links work once",
+			to:       uuid.Nil,
+			act:      func(s *Store, id string) { _, _ = s.Accept(id, recipient, now) },
+			acceptBy: stranger,
+			acceptAt: now,
+			wantErr:  ErrAlreadyStarted,
+		},
+		{
+			name:     "Expired challenge",
+			to:       recipient,
+			acceptBy: recipient,
+			acceptAt: now.Add(time.Hour),
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "Canceled challenge",
+			to:       recipient,
+			act:      func(s *Store, id string) { _ = s.Cancel(id, challenger, now) },
+			acceptBy: recipient,
+			acceptAt: now,
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:     "Declined challenge",
+			to:       recipient,
+			act:      func(s *Store, id string) { _ = s.Decline(id, recipient, now) },
+			acceptBy: recipient,
+			acceptAt: now,
+			wantErr:  ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore(time.Minute)
+			c, err := store.Create(Challenge{
+				From:     components.OnlinePlayerStruct{ID: challenger},
+				To:       tt.to,
+				FullTime: 600,
+				Color:    ColorRandom,
+			}, now)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if tt.act != nil {
+				tt.act(store, c.ID)
+			}
+
+			_, err = store.Accept(c.ID, tt.acceptBy, tt.acceptAt)
+			if err != tt.wantErr {
+				t.Errorf("Accept() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChallengeLifecycle(t *testing.T) {
+	challenger := uuid.New()
+	recipient := uuid.New()
+	now := time.Now()
+	store := NewStore(time.Minute)
+
+	if _, err := store.Create(Challenge{From: components.OnlinePlayerStruct{ID: challenger}, To: challenger}, now); err != ErrChallengeSelf {
+		t.Errorf("Create() for yourself error = %v, want %v", err, ErrChallengeSelf)
+	}
+
+	c, _ := store.Create(Challenge{From: components.OnlinePlayerStruct{ID: challenger}, To: recipient}, now)
+
+	incoming, outgoing := store.ForUser(recipient, now)
+	if len(incoming) != 1 || len(outgoing) != 0 {
+		t.Fatalf("ForUser(recipient) = %v incoming, %v outgoing, want 1, 0", len(incoming), len(outgoing))
+	}
+	incoming, outgoing = store.ForUser(challenger, now)
+	if len(incoming) != 0 || len(outgoing) != 1 {
+		t.Fatalf("ForUser(challenger) = %v incoming, %v outgoing, want 0, 1", len(incoming), len(outgoing))
+	}
+
+	if err := store.Cancel(c.ID, recipient, now); err != ErrNotAllowed {
+		t.Errorf("Cancel() by the recipient error = %v, want %v", err, ErrNotAllowed)
+	}
+
+	if _, err := store.Accept(c.ID, recipient, now); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if _, ok := store.TakeStarted(challenger, now); ok {
+		t.Errorf("TakeStarted() before the game started = true, want false")
+	}
+
+	store.Started(c.ID, "online:new", now)
+	incoming, _ = store.ForUser(recipient, now)
+	if len(incoming) != 0 {
+		t.Errorf("accepted challenge still listed")
+	}
+
+	game, ok := store.TakeStarted(challenger, now)
+	if !ok || game != "online:new" {
+		t.Errorf("TakeStarted() = %v, %v, want online:new, true", game, ok)
+	}
+	if _, ok := store.TakeStarted(challenger, now); ok {
+		t.Errorf("TakeStarted() twice = true, want false")
+	}
+}
@@ -0,0 +1,57 @@
+package charts
+
+import "github.com/NikolaTosic-sudo/chess-live/containers/components"
+
+const maxBarWidth = 12
+
+func TimeUsage(spent []int, width, height int) components.TimeChart {
+	chart := components.TimeChart{
+		Width:  width,
+		Height: height,
+		Bars:   []components.ChartBar{},
+	}
+
+	if len(spent) == 0 {
+		return chart
+	}
+
+	highest := 1
+	for _, seconds := range spent {
+		if seconds > highest {
+			highest = seconds
+		}
+	}
+
+	barWidth := min(width/len(spent), maxBarWidth)
+	if barWidth < 1 {
+		barWidth = 1
+	}
+
+	middle := height / 2
+
+	for i, seconds := range spent {
+		if seconds < 0 {
+			seconds = 0
+		}
+
+		barHeight := seconds * middle / highest
+		bar := components.ChartBar{
+			X:       i * barWidth,
+			Width:   barWidth,
+			Height:  barHeight,
+			IsWhite: i%2 == 0,
+			Ply:     i + 1,
+			Seconds: seconds,
+		}
+
+		if bar.IsWhite {
+			bar.Y = middle - barHeight
+		} else {
+			bar.Y = middle
+		}
+
+		chart.Bars = append(chart.Bars, bar)
+	}
+
+	return chart
+}
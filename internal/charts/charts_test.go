@@ -0,0 +1,72 @@
+package charts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+)
+
+func TestTimeUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		spent      []int
+		width      int
+		height     int
+		wantResult []components.ChartBar
+	}{
+		{
+			name:       "No moves",
+			spent:      []int{},
+			width:      240,
+			height:     120,
+			wantResult: []components.ChartBar{},
+		},
+		{
+			name:   "White bars go up and black bars go down",
+			spent:  []int{10, 5, 0, 20},
+			width:  240,
+			height: 120,
+			wantResult: []components.ChartBar{
+				{X: 0, Y: 30, Width: 12, Height: 30, IsWhite: true, Ply: 1, Seconds: 10},
+				{X: 12, Y: 60, Width: 12, Height: 15, IsWhite: false, Ply: 2, Seconds: 5},
+				{X: 24, Y: 60, Width: 12, Height: 0, IsWhite: true, Ply: 3, Seconds: 0},
+				{X: 36, Y: 60, Width: 12, Height: 60, IsWhite: false, Ply: 4, Seconds: 20},
+			},
+		},
+		{
+			name:   "Bars shrink to fit long games",
+			spent:  []int{4, 4, 4},
+			width:  6,
+			height: 10,
+			wantResult: []components.ChartBar{
+				{X: 0, Y: 0, Width: 2, Height: 5, IsWhite: true, Ply: 1, Seconds: 4},
+				{X: 2, Y: 5, Width: 2, Height: 5, IsWhite: false, Ply: 2, Seconds: 4},
+				{X: 4, Y: 0, Width: 2, Height: 5, IsWhite: true, Ply: 3, Seconds: 4},
+			},
+		},
+		{
+			name:   "Negative times are drawn as zero",
+			spent:  []int{-3},
+			width:  240,
+			height: 120,
+			wantResult: []components.ChartBar{
+				{X: 0, Y: 60, Width: 12, Height: 0, IsWhite: true, Ply: 1, Seconds: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := TimeUsage(tt.spent, tt.width, tt.height)
+
+			if chart.Width != tt.width || chart.Height != tt.height {
+				t.Errorf("TimeUsage() size = %vx%v, want %vx%v", chart.Width, chart.Height, tt.width, tt.height)
+			}
+
+			if !reflect.DeepEqual(chart.Bars, tt.wantResult) {
+				t.Errorf("TimeUsage() bars = %v, want %v", chart.Bars, tt.wantResult)
+			}
+		})
+	}
+}
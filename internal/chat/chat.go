@@ -0,0 +1,149 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MaxLength  = 140
+	rateLimit  = 5
+	rateWindow = 10 * time.Second
+)
+
+// Players and spectators of a match talk in separate channels.
+const (
+	ChannelPlayers    = "players"
+	ChannelSpectators = "spectators"
+)
+
+var (
+	ErrEmpty       = errors.New("message is empty")
+	ErrTooLong     = fmt.Errorf("messages can be at most %v characters", MaxLength)
+	ErrRateLimited = errors.New("you are sending messages too fast")
+)
+
+// Filter cleans a message up before it's delivered and stored.
+type Filter interface {
+	Clean(text string) string
+}
+
+// WordFilter masks blocked words, ignoring case.
+type WordFilter struct {
+	pattern *regexp.Regexp
+}
+
+func NewWordFilter(words ...string) *WordFilter {
+	var quoted []string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+
+	if len(quoted) == 0 {
+		return &WordFilter{}
+	}
+
+	return &WordFilter{
+		pattern: regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`),
+	}
+}
+
+func (f *WordFilter) Clean(text string) string {
+	if f.pattern == nil {
+		return text
+	}
+
+	return f.pattern.ReplaceAllStringFunc(text, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+}
+
+// Validate trims the message and checks that it isn't empty or too long.
+func Validate(text string) (string, error) {
+	text = strings.TrimSpace(text)
+
+	if text == "" {
+		return "", ErrEmpty
+	}
+
+	if utf8.RuneCountInString(text) > MaxLength {
+		return "", ErrTooLong
+	}
+
+	return text, nil
+}
+
+// Room keeps the chat state of a single match: who muted whom and when
+// everyone last sent a message.
+type Room struct {
+	mu    sync.Mutex
+	muted map[uuid.UUID]map[uuid.UUID]bool
+	sent  map[uuid.UUID][]time.Time
+}
+
+func NewRoom() *Room {
+	return &Room{
+		muted: make(map[uuid.UUID]map[uuid.UUID]bool),
+		sent:  make(map[uuid.UUID][]time.Time),
+	}
+}
+
+// ToggleMute mutes or unmutes user for by and reports whether user is now
+// muted.
+func (r *Room) ToggleMute(by, user uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.muted[by] == nil {
+		r.muted[by] = make(map[uuid.UUID]bool)
+	}
+
+	if r.muted[by][user] {
+		delete(r.muted[by], user)
+		return false
+	}
+
+	r.muted[by][user] = true
+
+	return true
+}
+
+func (r *Room) IsMuted(by, user uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.muted[by][user]
+}
+
+// Allow records a message from user at now, unless they already sent
+// rateLimit messages within the last rateWindow.
+func (r *Room) Allow(user uuid.UUID, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var recent []time.Time
+	for _, sent := range r.sent[user] {
+		if now.Sub(sent) < rateWindow {
+			recent = append(recent, sent)
+		}
+	}
+
+	if len(recent) >= rateLimit {
+		r.sent[user] = recent
+		return false
+	}
+
+	r.sent[user] = append(recent, now)
+
+	return true
+}
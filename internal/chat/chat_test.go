@@ -0,0 +1,150 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantText string
+		wantErr  error
+	}{
+		{
+			name:     "Regular message",
+			text:     "good luck",
+			wantText: "good luck",
+		},
+		{
+			name:     "Surrounding whitespace is trimmed",
+			text:     "  gg  ",
+			wantText: "gg",
+		},
+		{
+			name:    "Empty message",
+			text:    "   ",
+			wantErr: ErrEmpty,
+		},
+		{
+			name:     "Exactly the limit",
+			text:     strings.Repeat("é", MaxLength),
+			wantText: strings.Repeat("é", MaxLength),
+		},
+		{
+			name:    "Over the limit",
+			text:    strings.Repeat("a", MaxLength+1),
+			wantErr: ErrTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Validate(tt.text)
+			if err != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantText {
+				t.Errorf("Validate() = %q, want %q", got, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestWordFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *WordFilter
+		text   string
+		want   string
+	}{
+		{
+			name:   "No blocked words",
+			filter: NewWordFilter(),
+			text:   "darn it",
+			want:   "darn it",
+		},
+		{
+			name:   "Blocked word is masked",
+			filter: NewWordFilter("darn"),
+			text:   "darn it",
+			want:   "**** it",
+		},
+		{
+			name:   "Matching ignores case",
+			filter: NewWordFilter("darn"),
+			text:   "DaRn it",
+			want:   "**** it",
+		},
+		{
+			name:   "Only whole words are masked",
+			filter: NewWordFilter("ass"),
+			text:   "pass the ass",
+			want:   "pass the ***",
+		},
+		{
+			name:   "Empty entries are ignored",
+			filter: NewWordFilter("", " "),
+			text:   "hello",
+			want:   "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Clean(tt.text); got != tt.want {
+				t.Errorf("Clean() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToggleMute(t *testing.T) {
+	room := NewRoom()
+	white := uuid.New()
+	black := uuid.New()
+
+	if !room.ToggleMute(white, black) {
+		t.Fatalf("ToggleMute() = false, want true")
+	}
+	if !room.IsMuted(white, black) {
+		t.Errorf("IsMuted() = false after muting, want true")
+	}
+	if room.IsMuted(black, white) {
+		t.Errorf("IsMuted() = true for the other direction, want false")
+	}
+	if room.ToggleMute(white, black) {
+		t.Errorf("ToggleMute() = true when unmuting, want false")
+	}
+	if room.IsMuted(white, black) {
+		t.Errorf("IsMuted() = true after unmuting, want false")
+	}
+}
+
+func TestAllow(t *testing.T) {
+	room := NewRoom()
+	user := uuid.New()
+	start := time.Now()
+
+	for i := range rateLimit {
+		if !room.Allow(user, start.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("Allow() #%v = false, want true", i)
+		}
+	}
+
+	if room.Allow(user, start.Add(rateLimit*time.Second)) {
+		t.Errorf("Allow() over the limit = true, want false")
+	}
+
+	if !room.Allow(uuid.New(), start) {
+		t.Errorf("Allow() for another user = false, want true")
+	}
+
+	if !room.Allow(user, start.Add(rateWindow)) {
+		t.Errorf("Allow() after the window moved = false, want true")
+	}
+}
@@ -12,13 +12,19 @@ import (
 )
 
 const createMatch = `-- name: CreateMatch :one
-INSERT INTO matches(white, black, full_time, is_online, result, created_at)
+INSERT INTO matches(white, black, full_time, is_online, result, addition, variant, rated, start_fen, white_id, black_id, created_at)
 VALUES(
   $1,
   $2,
   $3,
   $4,
   $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $11,
   NOW()
 ) RETURNING id
 `
@@ -29,6 +35,12 @@ type CreateMatchParams struct {
 	FullTime int32
 	IsOnline bool
 	Result   string
+	Addition int32
+	Variant  string
+	Rated    bool
+	StartFen string
+	WhiteID  uuid.NullUUID
+	BlackID  uuid.NullUUID
 }
 
 func (q *Queries) CreateMatch(ctx context.Context, arg CreateMatchParams) (int32, error) {
@@ -38,6 +50,12 @@ func (q *Queries) CreateMatch(ctx context.Context, arg CreateMatchParams) (int32
 		arg.FullTime,
 		arg.IsOnline,
 		arg.Result,
+		arg.Addition,
+		arg.Variant,
+		arg.Rated,
+		arg.StartFen,
+		arg.WhiteID,
+		arg.BlackID,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -45,7 +63,7 @@ func (q *Queries) CreateMatch(ctx context.Context, arg CreateMatchParams) (int32
 }
 
 const getAllMatchesForUser = `-- name: GetAllMatchesForUser :many
-SELECT id, white, black, full_time, is_online, result, ended, created_at FROM matches WHERE id IN (
+SELECT id, white, black, full_time, is_online, result, ended, created_at, termination, addition, variant, rated, start_fen, ended_at, white_id, black_id FROM matches WHERE id IN (
  SELECT match_id FROM matches_users WHERE user_id = $1
 ) ORDER BY created_at DESC LIMIT 30
 `
@@ -68,6 +86,14 @@ func (q *Queries) GetAllMatchesForUser(ctx context.Context, userID uuid.UUID) ([
 			&i.Result,
 			&i.Ended,
 			&i.CreatedAt,
+			&i.Termination,
+			&i.Addition,
+			&i.Variant,
+			&i.Rated,
+			&i.StartFen,
+			&i.EndedAt,
+			&i.WhiteID,
+			&i.BlackID,
 		); err != nil {
 			return nil, err
 		}
@@ -83,7 +109,7 @@ func (q *Queries) GetAllMatchesForUser(ctx context.Context, userID uuid.UUID) ([
 }
 
 const getMatchById = `-- name: GetMatchById :one
-SELECT id, white, black, full_time, is_online, result, ended, created_at FROM matches WHERE id = $1
+SELECT id, white, black, full_time, is_online, result, ended, created_at, termination, addition, variant, rated, start_fen, ended_at, white_id, black_id FROM matches WHERE id = $1
 `
 
 func (q *Queries) GetMatchById(ctx context.Context, id int32) (Match, error) {
@@ -98,21 +124,67 @@ func (q *Queries) GetMatchById(ctx context.Context, id int32) (Match, error) {
 		&i.Result,
 		&i.Ended,
 		&i.CreatedAt,
+		&i.Termination,
+		&i.Addition,
+		&i.Variant,
+		&i.Rated,
+		&i.StartFen,
+		&i.EndedAt,
+		&i.WhiteID,
+		&i.BlackID,
 	)
 	return i, err
 }
 
 const updateMatchOnEnd = `-- name: UpdateMatchOnEnd :exec
-UPDATE matches SET ended = true, result = $1
-WHERE id = $2
+UPDATE matches SET ended = true, result = $1, termination = $2, ended_at = NOW()
+WHERE id = $3
 `
 
 type UpdateMatchOnEndParams struct {
-	Result string
-	ID     int32
+	Result      string
+	Termination string
+	ID          int32
 }
 
 func (q *Queries) UpdateMatchOnEnd(ctx context.Context, arg UpdateMatchOnEndParams) error {
-	_, err := q.db.ExecContext(ctx, updateMatchOnEnd, arg.Result, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateMatchOnEnd, arg.Result, arg.Termination, arg.ID)
+	return err
+}
+
+const isMatchEndedForUpdate = `-- name: IsMatchEndedForUpdate :one
+SELECT ended FROM matches WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) IsMatchEndedForUpdate(ctx context.Context, id int32) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isMatchEndedForUpdate, id)
+	var ended bool
+	err := row.Scan(&ended)
+	return ended, err
+}
+
+const reassignMatchPlayer = `-- name: ReassignMatchPlayer :exec
+UPDATE matches SET
+  white = CASE WHEN white_id = $1 THEN $2 ELSE white END,
+  black = CASE WHEN black_id = $1 THEN $2 ELSE black END,
+  white_id = CASE WHEN white_id = $1 THEN $3 ELSE white_id END,
+  black_id = CASE WHEN black_id = $1 THEN $3 ELSE black_id END
+WHERE id = $4
+`
+
+type ReassignMatchPlayerParams struct {
+	OldID   uuid.NullUUID
+	NewName string
+	NewID   uuid.NullUUID
+	ID      int32
+}
+
+func (q *Queries) ReassignMatchPlayer(ctx context.Context, arg ReassignMatchPlayerParams) error {
+	_, err := q.db.ExecContext(ctx, reassignMatchPlayer,
+		arg.OldID,
+		arg.NewName,
+		arg.NewID,
+		arg.ID,
+	)
 	return err
 }
@@ -42,3 +42,22 @@ func (q *Queries) DeleteMatchUser(ctx context.Context, arg DeleteMatchUserParams
 	_, err := q.db.ExecContext(ctx, deleteMatchUser, arg.UserID, arg.MatchID)
 	return err
 }
+
+const claimMatchForUser = `-- name: ClaimMatchForUser :execrows
+UPDATE matches_users SET user_id = $1
+WHERE match_id = $2 AND user_id = $3
+`
+
+type ClaimMatchForUserParams struct {
+	UserID  uuid.UUID
+	MatchID int32
+	GuestID uuid.UUID
+}
+
+func (q *Queries) ClaimMatchForUser(ctx context.Context, arg ClaimMatchForUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimMatchForUser, arg.UserID, arg.MatchID, arg.GuestID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
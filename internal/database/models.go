@@ -12,17 +12,41 @@ import (
 	"github.com/google/uuid"
 )
 
-type Match struct {
+type ChatMessage struct {
 	ID        int32
-	White     string
-	Black     string
-	FullTime  int32
-	IsOnline  bool
-	Result    string
-	Ended     bool
+	MatchID   int32
+	UserID    uuid.UUID
+	Channel   string
+	Body      string
 	CreatedAt time.Time
 }
 
+type LiveMatch struct {
+	Game      string
+	Ply       int32
+	State     json.RawMessage
+	UpdatedAt time.Time
+}
+
+type Match struct {
+	ID          int32
+	White       string
+	Black       string
+	FullTime    int32
+	IsOnline    bool
+	Result      string
+	Ended       bool
+	CreatedAt   time.Time
+	Termination string
+	Addition    int32
+	Variant     string
+	Rated       bool
+	StartFen    string
+	EndedAt     sql.NullTime
+	WhiteID     uuid.NullUUID
+	BlackID     uuid.NullUUID
+}
+
 type MatchesUser struct {
 	MatchID int32
 	UserID  uuid.UUID
@@ -36,6 +60,30 @@ type Move struct {
 	BlackTime int32
 	MatchID   int32
 	CreatedAt time.Time
+	TimeSpent int32
+	DrawOffer bool
+	LagMs     int32
+}
+
+type Rating struct {
+	UserID     uuid.UUID
+	Category   string
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+	Games      int32
+	UpdatedAt  time.Time
+}
+
+type RatingHistory struct {
+	ID           int32
+	UserID       uuid.UUID
+	MatchID      int32
+	Category     string
+	RatingBefore float64
+	RatingAfter  float64
+	Deviation    float64
+	CreatedAt    time.Time
 }
 
 type RefreshToken struct {
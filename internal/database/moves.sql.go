@@ -11,13 +11,16 @@ import (
 )
 
 const createMove = `-- name: CreateMove :exec
-INSERT INTO moves(board, move, white_time, black_time, match_id, created_at)
+INSERT INTO moves(board, move, white_time, black_time, match_id, time_spent, draw_offer, lag_ms, created_at)
 VALUES(
   $1,
   $2,
   $3,
   $4,
   $5,
+  $6,
+  $7,
+  $8,
   NOW()
 )
 `
@@ -28,6 +31,9 @@ type CreateMoveParams struct {
 	WhiteTime int32
 	BlackTime int32
 	MatchID   int32
+	TimeSpent int32
+	DrawOffer bool
+	LagMs     int32
 }
 
 func (q *Queries) CreateMove(ctx context.Context, arg CreateMoveParams) error {
@@ -37,10 +43,31 @@ func (q *Queries) CreateMove(ctx context.Context, arg CreateMoveParams) error {
 		arg.WhiteTime,
 		arg.BlackTime,
 		arg.MatchID,
+		arg.TimeSpent,
+		arg.DrawOffer,
+		arg.LagMs,
 	)
 	return err
 }
 
+const deleteLatestMoves = `-- name: DeleteLatestMoves :exec
+DELETE FROM moves WHERE id IN (
+  SELECT id FROM moves WHERE match_id = $1
+  ORDER BY id DESC
+  LIMIT $2
+)
+`
+
+type DeleteLatestMovesParams struct {
+	MatchID int32
+	Limit   int32
+}
+
+func (q *Queries) DeleteLatestMoves(ctx context.Context, arg DeleteLatestMovesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteLatestMoves, arg.MatchID, arg.Limit)
+	return err
+}
+
 const getAllMovesForMatch = `-- name: GetAllMovesForMatch :many
 SELECT move FROM moves WHERE match_id = $1
 `
@@ -107,6 +134,48 @@ func (q *Queries) GetLatestMoveForMatch(ctx context.Context, matchID int32) (Get
 	return i, err
 }
 
+const getMoveTimesForMatch = `-- name: GetMoveTimesForMatch :many
+SELECT move, time_spent, white_time, black_time, draw_offer FROM moves WHERE match_id = $1
+ORDER BY id
+`
+
+type GetMoveTimesForMatchRow struct {
+	Move      string
+	TimeSpent int32
+	WhiteTime int32
+	BlackTime int32
+	DrawOffer bool
+}
+
+func (q *Queries) GetMoveTimesForMatch(ctx context.Context, matchID int32) ([]GetMoveTimesForMatchRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMoveTimesForMatch, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMoveTimesForMatchRow
+	for rows.Next() {
+		var i GetMoveTimesForMatchRow
+		if err := rows.Scan(
+			&i.Move,
+			&i.TimeSpent,
+			&i.WhiteTime,
+			&i.BlackTime,
+			&i.DrawOffer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNumberOfMovesPerMatch = `-- name: GetNumberOfMovesPerMatch :one
 SELECT COUNT(*) FROM moves WHERE match_id = $1
 `
@@ -118,6 +187,19 @@ func (q *Queries) GetNumberOfMovesPerMatch(ctx context.Context, matchID int32) (
 	return count, err
 }
 
+const markLatestMoveDrawOffer = `-- name: MarkLatestMoveDrawOffer :exec
+UPDATE moves SET draw_offer = TRUE WHERE id = (
+  SELECT id FROM moves WHERE match_id = $1
+  ORDER BY id DESC
+  LIMIT 1
+)
+`
+
+func (q *Queries) MarkLatestMoveDrawOffer(ctx context.Context, matchID int32) error {
+	_, err := q.db.ExecContext(ctx, markLatestMoveDrawOffer, matchID)
+	return err
+}
+
 const updateBoardForMove = `-- name: UpdateBoardForMove :exec
 UPDATE moves SET board = $1 WHERE match_id = $2 AND move = $3
 `
@@ -77,3 +77,37 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (User, error) {
 	)
 	return i, err
 }
+
+const getUsersByName = `-- name: GetUsersByName :many
+SELECT id, created_at, updated_at, name, email, hashed_password FROM users WHERE name = $1 LIMIT 2
+`
+
+func (q *Queries) GetUsersByName(ctx context.Context, name string) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Email,
+			&i.HashedPassword,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
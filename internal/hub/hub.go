@@ -0,0 +1,385 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	sendBufferSize = 64
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	// Pings double as lag probes, so they go out far more often than
+	// pongWait alone would need.
+	pingPeriod = 5 * time.Second
+)
+
+// Hub fans messages out to every connection subscribed to a single match.
+// Each connection has its own buffered queue drained by one writer goroutine,
+// so a message is written exactly once per subscriber and a websocket.Conn is
+// only ever written to from one place.
+type Hub struct {
+	mu           sync.RWMutex
+	clients      map[uuid.UUID]*Client
+	closed       bool
+	writers      sync.WaitGroup
+	onConnect    func(uuid.UUID)
+	onDisconnect func(uuid.UUID)
+	onMessage    func(uuid.UUID, []byte)
+	pongWait     time.Duration
+	pingPeriod   time.Duration
+}
+
+// Client is a single subscriber connection. A client whose queue fills up is
+// treated as a slow consumer and disconnected rather than blocking the sender.
+type Client struct {
+	ID         uuid.UUID
+	conn       *websocket.Conn
+	send       chan []byte
+	done       chan struct{}
+	once       sync.Once
+	pongWait   time.Duration
+	pingPeriod time.Duration
+	roundTrip  atomic.Int64
+}
+
+func New() *Hub {
+	return &Hub{
+		clients:    make(map[uuid.UUID]*Client),
+		pongWait:   pongWait,
+		pingPeriod: pingPeriod,
+	}
+}
+
+func newClient(id uuid.UUID, conn *websocket.Conn, size int) *Client {
+	return &Client{
+		ID:         id,
+		conn:       conn,
+		send:       make(chan []byte, size),
+		done:       make(chan struct{}),
+		pongWait:   pongWait,
+		pingPeriod: pingPeriod,
+	}
+}
+
+// OnConnect registers fn to be called with the player's id every time they
+// open a connection, including reconnects.
+func (h *Hub) OnConnect(fn func(uuid.UUID)) {
+	h.mu.Lock()
+	h.onConnect = fn
+	h.mu.Unlock()
+}
+
+// OnDisconnect registers fn to be called with the player's id whenever their
+// connection drops or stops answering pings.
+func (h *Hub) OnDisconnect(fn func(uuid.UUID)) {
+	h.mu.Lock()
+	h.onDisconnect = fn
+	h.mu.Unlock()
+}
+
+// OnMessage registers fn to be called with every message a player sends. It
+// runs on the connection's reader, so one player's messages arrive in order.
+func (h *Hub) OnMessage(fn func(uuid.UUID, []byte)) {
+	h.mu.Lock()
+	h.onMessage = fn
+	h.mu.Unlock()
+}
+
+// Serve subscribes conn for the player and blocks until the connection ends.
+// Closing normally, being replaced by a newer connection or the hub shutting
+// down are not disconnects; anything else fires the OnDisconnect handler.
+func (h *Hub) Serve(id uuid.UUID, conn *websocket.Conn) {
+	client := h.Subscribe(id, conn)
+
+	h.mu.RLock()
+	onConnect := h.onConnect
+	onDisconnect := h.onDisconnect
+	onMessage := h.onMessage
+	h.mu.RUnlock()
+
+	if onConnect != nil {
+		onConnect(id)
+	}
+
+	err := client.ReadPump(func(msg []byte) {
+		if onMessage != nil {
+			onMessage(id, msg)
+		}
+	})
+
+	if h.Unsubscribe(client) && !websocket.IsCloseError(err, websocket.CloseNormalClosure) && onDisconnect != nil {
+		onDisconnect(id)
+	}
+}
+
+func (h *Hub) Subscribe(id uuid.UUID, conn *websocket.Conn) *Client {
+	client := newClient(id, conn, sendBufferSize)
+	client.pongWait = h.pongWait
+	client.pingPeriod = h.pingPeriod
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		client.Close()
+		go client.writePump()
+		return client
+	}
+	previous := h.clients[id]
+	h.clients[id] = client
+	h.writers.Add(1)
+	h.mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+
+	go func() {
+		defer h.writers.Done()
+		client.writePump()
+	}()
+
+	return client
+}
+
+// Unsubscribe removes the client and reports whether it was still the current
+// subscription for its player, as opposed to replaced or shut down by the hub.
+func (h *Hub) Unsubscribe(client *Client) bool {
+	h.mu.Lock()
+	current := h.clients[client.ID] == client
+	if current {
+		delete(h.clients, client.ID)
+	}
+	h.mu.Unlock()
+
+	client.Close()
+
+	return current
+}
+
+func (h *Hub) Broadcast(msg string) {
+	for _, client := range h.subscribers() {
+		client.Send([]byte(msg))
+	}
+}
+
+func (h *Hub) Send(id uuid.UUID, msg string) bool {
+	h.mu.RLock()
+	client, ok := h.clients[id]
+	h.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	return client.Send([]byte(msg))
+}
+
+func (h *Hub) IsConnected(id uuid.UUID) bool {
+	h.mu.RLock()
+	client, ok := h.clients[id]
+	h.mu.RUnlock()
+
+	return ok && !client.isClosed()
+}
+
+// RoundTrip is the player's connection latency, smoothed over the last few
+// pings. It reports false until a pong came back on their current connection.
+func (h *Hub) RoundTrip(id uuid.UUID) (time.Duration, bool) {
+	h.mu.RLock()
+	client, ok := h.clients[id]
+	h.mu.RUnlock()
+
+	if !ok || client.isClosed() {
+		return 0, false
+	}
+
+	rtt := time.Duration(client.roundTrip.Load())
+
+	return rtt, rtt > 0
+}
+
+// Subscribers lists the players with an open connection, for messages that
+// differ per player.
+func (h *Hub) Subscribers() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, client := range h.subscribers() {
+		if !client.isClosed() {
+			ids = append(ids, client.ID)
+		}
+	}
+
+	return ids
+}
+
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	clients := h.clients
+	h.clients = make(map[uuid.UUID]*Client)
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// Shutdown closes the hub and waits until every connection got what was still
+// queued for it and the close frame, or until ctx is done.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.Close()
+
+	drained := make(chan struct{})
+	go func() {
+		h.writers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) subscribers() []*Client {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
+func (c *Client) Send(msg []byte) bool {
+	if c.isClosed() {
+		return false
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.Close()
+		return false
+	}
+}
+
+func (c *Client) Close() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// ReadPump hands every incoming message to onMessage until the connection
+// fails, the client is closed or no pong arrives within pongWait, and returns
+// the error that ended it.
+func (c *Client) ReadPump(onMessage func([]byte)) error {
+	defer c.Close()
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	c.conn.SetPongHandler(func(appData string) error {
+		c.recordPong(appData, time.Now())
+		return c.conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		onMessage(msg)
+	}
+}
+
+// recordPong measures the round trip of the ping the pong answers, which
+// carries the time it was sent.
+func (c *Client) recordPong(appData string, now time.Time) {
+	sent, err := strconv.ParseInt(appData, 10, 64)
+	if err != nil {
+		return
+	}
+	sample := now.Sub(time.Unix(0, sent))
+	if sample <= 0 {
+		return
+	}
+
+	previous := time.Duration(c.roundTrip.Load())
+	if previous > 0 {
+		sample = (previous*3 + sample) / 4
+	}
+	c.roundTrip.Store(int64(sample))
+}
+
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) writePump() {
+	if c.conn == nil {
+		return
+	}
+	ticker := time.NewTicker(c.pingPeriod)
+	defer func() {
+		ticker.Stop()
+		_ = c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			sent := strconv.FormatInt(time.Now().UnixNano(), 10)
+			if err := c.conn.WriteMessage(websocket.PingMessage, []byte(sent)); err != nil {
+				c.Close()
+				return
+			}
+		case <-c.done:
+			c.flush()
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return
+		}
+	}
+}
+
+// flush writes whatever is still queued, so messages sent right before the
+// hub is closed, like the end of game modal, still reach the player.
+func (c *Client) flush() {
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,522 @@
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func startHubServer(t *testing.T, h *Hub) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.URL.Query().Get("id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		h.Serve(id, conn)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func dial(t *testing.T, server *httptest.Server, h *Hub, id uuid.UUID) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?id=" + id.String()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	deadline := time.Now().Add(time.Second)
+	for !h.IsConnected(id) {
+		if time.Now().After(deadline) {
+			t.Fatalf("client %v never subscribed", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) (string, error) {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, msg, err := conn.ReadMessage()
+
+	return string(msg), err
+}
+
+func TestBroadcastAndSend(t *testing.T) {
+	h := New()
+	server := startHubServer(t, h)
+
+	white := uuid.New()
+	black := uuid.New()
+	whiteConn := dial(t, server, h, white)
+	blackConn := dial(t, server, h, black)
+
+	h.Broadcast("everyone")
+	if !h.Send(black, "black only") {
+		t.Fatalf("Send() = false, want true")
+	}
+	h.Broadcast("everyone again")
+
+	tests := []struct {
+		name string
+		conn *websocket.Conn
+		want []string
+	}{
+		{
+			name: "White receives broadcasts once",
+			conn: whiteConn,
+			want: []string{"everyone", "everyone again"},
+		},
+		{
+			name: "Black receives broadcasts and its own message in order",
+			conn: blackConn,
+			want: []string{"everyone", "black only", "everyone again"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range tt.want {
+				got, err := readMessage(t, tt.conn)
+				if err != nil {
+					t.Fatalf("ReadMessage() error = %v", err)
+				}
+				if got != want {
+					t.Errorf("ReadMessage() = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSendToUnknownPlayer(t *testing.T) {
+	h := New()
+
+	if h.Send(uuid.New(), "hello") {
+		t.Errorf("Send() = true, want false")
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	h := New()
+	server := startHubServer(t, h)
+
+	if got := h.Subscribers(); len(got) != 0 {
+		t.Fatalf("Subscribers() = %v, want none", got)
+	}
+
+	id := uuid.New()
+	conn := dial(t, server, h, id)
+
+	if got := h.Subscribers(); len(got) != 1 || got[0] != id {
+		t.Fatalf("Subscribers() = %v, want [%v]", got, id)
+	}
+
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	deadline := time.Now().Add(time.Second)
+	for len(h.Subscribers()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Subscribers() = %v after closing, want none", h.Subscribers())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSlowConsumerIsDisconnected(t *testing.T) {
+	client := newClient(uuid.New(), nil, 2)
+
+	for i := range 2 {
+		if !client.Send([]byte("move")) {
+			t.Fatalf("Send() #%v = false, want true", i)
+		}
+	}
+
+	if client.Send([]byte("move")) {
+		t.Errorf("Send() on a full queue = true, want false")
+	}
+
+	select {
+	case <-client.Done():
+	default:
+		t.Errorf("slow client wasn't closed")
+	}
+
+	if client.Send([]byte("move")) {
+		t.Errorf("Send() on a closed client = true, want false")
+	}
+}
+
+func TestSubscribeReplacesPreviousConnection(t *testing.T) {
+	h := New()
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	first := dial(t, server, h, id)
+
+	h.mu.RLock()
+	firstClient := h.clients[id]
+	h.mu.RUnlock()
+
+	second := dial(t, server, h, id)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		h.mu.RLock()
+		replaced := h.clients[id] != firstClient
+		h.mu.RUnlock()
+		if replaced {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("second connection never replaced the first")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := readMessage(t, first); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("first connection error = %v, want normal closure", err)
+	}
+
+	if h.Unsubscribe(firstClient) {
+		t.Errorf("Unsubscribe() of a replaced client = true, want false")
+	}
+
+	h.Broadcast("still here")
+	if got, err := readMessage(t, second); err != nil || got != "still here" {
+		t.Errorf("ReadMessage() = %q, %v, want %q", got, err, "still here")
+	}
+}
+
+func TestClose(t *testing.T) {
+	h := New()
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	conn := dial(t, server, h, id)
+
+	h.Close()
+
+	if _, err := readMessage(t, conn); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() error = %v, want normal closure", err)
+	}
+
+	if h.IsConnected(id) {
+		t.Errorf("IsConnected() = true after Close, want false")
+	}
+
+	late := h.Subscribe(uuid.New(), nil)
+	select {
+	case <-late.Done():
+	default:
+		t.Errorf("Subscribe() on a closed hub returned an open client")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	h := New()
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	conn := dial(t, server, h, id)
+
+	h.Broadcast("last words")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if got, err := readMessage(t, conn); err != nil || got != "last words" {
+		t.Errorf("ReadMessage() = %q, %v, want %q", got, err, "last words")
+	}
+	if _, err := readMessage(t, conn); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() error = %v, want normal closure", err)
+	}
+
+	if err := New().Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() of an empty hub error = %v", err)
+	}
+}
+
+func watchDisconnects(h *Hub) chan uuid.UUID {
+	disconnected := make(chan uuid.UUID, 4)
+	h.OnDisconnect(func(id uuid.UUID) {
+		disconnected <- id
+	})
+
+	return disconnected
+}
+
+func TestDisconnectEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		act            func(conn *websocket.Conn)
+		wantDisconnect bool
+	}{
+		{
+			name: "Peer answering pings stays connected",
+			act: func(conn *websocket.Conn) {
+				go func() {
+					for {
+						if _, _, err := conn.ReadMessage(); err != nil {
+							return
+						}
+					}
+				}()
+			},
+			wantDisconnect: false,
+		},
+		{
+			name:           "Silent peer is disconnected",
+			act:            func(conn *websocket.Conn) {},
+			wantDisconnect: true,
+		},
+		{
+			name: "Dropped connection is disconnected",
+			act: func(conn *websocket.Conn) {
+				_ = conn.NetConn().Close()
+			},
+			wantDisconnect: true,
+		},
+		{
+			name: "Normal close isn't a disconnect",
+			act: func(conn *websocket.Conn) {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			},
+			wantDisconnect: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New()
+			h.pongWait = 100 * time.Millisecond
+			h.pingPeriod = 40 * time.Millisecond
+			disconnected := watchDisconnects(h)
+			server := startHubServer(t, h)
+
+			id := uuid.New()
+			conn := dial(t, server, h, id)
+
+			tt.act(conn)
+
+			select {
+			case got := <-disconnected:
+				if !tt.wantDisconnect {
+					t.Fatalf("OnDisconnect() called for %v, want no disconnect", got)
+				}
+				if got != id {
+					t.Errorf("OnDisconnect() id = %v, want %v", got, id)
+				}
+				if h.IsConnected(id) {
+					t.Errorf("IsConnected() = true after disconnect, want false")
+				}
+			case <-time.After(500 * time.Millisecond):
+				if tt.wantDisconnect {
+					t.Fatalf("OnDisconnect() wasn't called")
+				}
+			}
+		})
+	}
+}
+
+func TestReplacedConnectionIsNotDisconnect(t *testing.T) {
+	h := New()
+	disconnected := watchDisconnects(h)
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	first := dial(t, server, h, id)
+	_ = dial(t, server, h, id)
+
+	if _, err := readMessage(t, first); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Fatalf("first connection error = %v, want normal closure", err)
+	}
+
+	select {
+	case got := <-disconnected:
+		t.Errorf("OnDisconnect() called for %v, want no disconnect", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConnectEvents(t *testing.T) {
+	h := New()
+	connected := make(chan uuid.UUID, 4)
+	h.OnConnect(func(id uuid.UUID) {
+		connected <- id
+	})
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	_ = dial(t, server, h, id)
+	_ = dial(t, server, h, id)
+
+	for i := range 2 {
+		select {
+		case got := <-connected:
+			if got != id {
+				t.Errorf("OnConnect() id = %v, want %v", got, id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("OnConnect() call #%v never happened", i)
+		}
+	}
+}
+
+func TestMessagesReachHandlerInOrder(t *testing.T) {
+	h := New()
+	received := make(chan string, 4)
+	h.OnMessage(func(id uuid.UUID, msg []byte) {
+		received <- id.String() + ":" + string(msg)
+	})
+	server := startHubServer(t, h)
+
+	id := uuid.New()
+	conn := dial(t, server, h, id)
+
+	for _, msg := range []string{"first", "second"} {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("WriteMessage() error = %v", err)
+		}
+	}
+
+	for _, want := range []string{id.String() + ":first", id.String() + ":second"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("OnMessage() = %v, want %v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("OnMessage() never received %v", want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		answers  bool
+		wantLag  bool
+		stranger bool
+	}{
+		{
+			name:    "Peer answering pings is measured",
+			answers: true,
+			wantLag: true,
+		},
+		{
+			name:    "Silent peer isn't measured",
+			answers: false,
+			wantLag: false,
+		},
+		{
+			name:     "Unknown player isn't measured",
+			answers:  true,
+			wantLag:  false,
+			stranger: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New()
+			h.pingPeriod = 10 * time.Millisecond
+			server := startHubServer(t, h)
+
+			id := uuid.New()
+			conn := dial(t, server, h, id)
+			if tt.answers {
+				go func() {
+					for {
+						if _, _, err := conn.ReadMessage(); err != nil {
+							return
+						}
+					}
+				}()
+			}
+			if tt.stranger {
+				id = uuid.New()
+			}
+
+			var got time.Duration
+			var ok bool
+			deadline := time.Now().Add(200 * time.Millisecond)
+			for !ok && time.Now().Before(deadline) {
+				got, ok = h.RoundTrip(id)
+				time.Sleep(time.Millisecond)
+			}
+
+			if ok != tt.wantLag {
+				t.Fatalf("RoundTrip() ok = %v, want %v", ok, tt.wantLag)
+			}
+			if ok && (got <= 0 || got > time.Second) {
+				t.Errorf("RoundTrip() = %v, want a small positive duration", got)
+			}
+		})
+	}
+}
+
+func TestRecordPong(t *testing.T) {
+	sent := time.Unix(0, 1_000_000_000)
+	tests := []struct {
+		name     string
+		previous time.Duration
+		appData  string
+		want     time.Duration
+	}{
+		{
+			name:    "First sample is taken as is",
+			appData: "1000000000",
+			want:    80 * time.Millisecond,
+		},
+		{
+			name:     "Later samples are smoothed",
+			previous: 40 * time.Millisecond,
+			appData:  "1000000000",
+			want:     50 * time.Millisecond,
+		},
+		{
+			name:     "Foreign payload is ignored",
+			previous: 40 * time.Millisecond,
+			appData:  "hello",
+			want:     40 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(uuid.New(), nil, 1)
+			c.roundTrip.Store(int64(tt.previous))
+
+			c.recordPong(tt.appData, sent.Add(80*time.Millisecond))
+
+			if got := time.Duration(c.roundTrip.Load()); got != tt.want {
+				t.Errorf("recordPong() round trip = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package matches
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/google/uuid"
+)
+
+var ErrNotAbortable = errors.New("the game can't be aborted once both players moved")
+
+// bothMoved reports whether both players made their first move.
+func (m *Match) bothMoved() bool {
+	return len(m.AllMoves) >= 2
+}
+
+// Abortable reports whether the online game can still end without a result,
+// which it can until both players made their first move.
+func (m *Match) Abortable() bool {
+	return m.IsOnline && m.Result == "" && !m.bothMoved()
+}
+
+// Abort ends the game without a result on behalf of one of its players.
+func (m *Match) Abort(player uuid.UUID) error {
+	if !m.IsPlayer(player) {
+		return ErrNotAPlayer
+	}
+	if !m.Abortable() {
+		return ErrNotAbortable
+	}
+
+	m.PublishGameEnd("*", TerminationAborted)
+
+	return nil
+}
+
+// FirstMoveDeadline is when the game gets aborted if the player to move
+// hasn't made their first move by then.
+func (m *Match) FirstMoveDeadline() (time.Time, bool) {
+	if !m.Abortable() || m.Online.FirstMoveTimeout <= 0 {
+		return time.Time{}, false
+	}
+
+	return m.TurnStarted.Add(m.Online.FirstMoveTimeout), true
+}
+
+// AbortIfFirstMoveMissed aborts the game if the player to move let the
+// deadline for their first move pass.
+func (m *Match) AbortIfFirstMoveMissed(now time.Time) bool {
+	deadline, ok := m.FirstMoveDeadline()
+	if !ok || now.Before(deadline) {
+		return false
+	}
+
+	m.PublishGameEnd("*", TerminationAborted)
+
+	return true
+}
+
+// dropAbort takes the abort button away once both players moved.
+func (m *Match) dropAbort(w io.Writer) {
+	if !m.IsOnline || len(m.AllMoves) != 2 {
+		return
+	}
+
+	err := m.SendToPlayers(w, `<div id="abort" hx-swap-oob="outerHTML"></div>`)
+	if err != nil {
+		responses.LogError("couldn't remove the abort button", err)
+	}
+}
@@ -0,0 +1,137 @@
+package matches
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestAbort(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	tests := []struct {
+		name    string
+		moves   []string
+		result  string
+		player  uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "Before any move",
+			player: white,
+		},
+		{
+			name:   "Before the second move",
+			moves:  []string{"e4"},
+			player: white,
+		},
+		{
+			name:    "After both moved",
+			moves:   []string{"e4", "e5"},
+			player:  black,
+			wantErr: ErrNotAbortable,
+		},
+		{
+			name:    "Game already over",
+			result:  "0-1",
+			player:  white,
+			wantErr: ErrNotAbortable,
+		},
+		{
+			name:    "Not a player",
+			player:  uuid.New(),
+			wantErr: ErrNotAPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{
+				AllMoves: tt.moves,
+				Result:   tt.result,
+				IsOnline: true,
+				Online: OnlineGame{
+					Players: map[string]components.OnlinePlayerStruct{
+						"white": {ID: white},
+						"black": {ID: black},
+					},
+				},
+			}
+
+			err := match.Abort(tt.player)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Abort() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if match.Result != "*" || match.Termination != TerminationAborted {
+				t.Errorf("Abort() ended with %q by %q, want * by %q", match.Result, match.Termination, TerminationAborted)
+			}
+		})
+	}
+}
+
+func TestAbortIfFirstMoveMissed(t *testing.T) {
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		moves       []string
+		timeout     time.Duration
+		now         time.Time
+		wantAborted bool
+	}{
+		{
+			name:    "Within the time",
+			timeout: 30 * time.Second,
+			now:     started.Add(29 * time.Second),
+		},
+		{
+			name:        "Time ran out",
+			timeout:     30 * time.Second,
+			now:         started.Add(30 * time.Second),
+			wantAborted: true,
+		},
+		{
+			name:        "Second player's time ran out",
+			moves:       []string{"e4"},
+			timeout:     30 * time.Second,
+			now:         started.Add(time.Minute),
+			wantAborted: true,
+		},
+		{
+			name:    "Both moved",
+			moves:   []string{"e4", "e5"},
+			timeout: 30 * time.Second,
+			now:     started.Add(time.Minute),
+		},
+		{
+			name: "No timeout set",
+			now:  started.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{
+				AllMoves:    tt.moves,
+				TurnStarted: started,
+				IsOnline:    true,
+				Online:      OnlineGame{FirstMoveTimeout: tt.timeout},
+			}
+
+			if got := match.AbortIfFirstMoveMissed(tt.now); got != tt.wantAborted {
+				t.Fatalf("AbortIfFirstMoveMissed() = %v, want %v", got, tt.wantAborted)
+			}
+			if tt.wantAborted && match.Termination != TerminationAborted {
+				t.Errorf("AbortIfFirstMoveMissed() termination = %q, want %q", match.Termination, TerminationAborted)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package matches
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/google/uuid"
+)
+
+// ChatChannel returns the hub a chat channel is delivered on and everyone
+// who is listening to it.
+func (m *Match) ChatChannel(channel string) (*hub.Hub, []uuid.UUID) {
+	switch channel {
+	case chat.ChannelPlayers:
+		var ids []uuid.UUID
+		for _, player := range m.Online.Players {
+			if player.ID != uuid.Nil {
+				ids = append(ids, player.ID)
+			}
+		}
+		return m.Online.Hub, ids
+	case chat.ChannelSpectators:
+		return m.Online.Spectators, slices.Collect(maps.Keys(m.Online.SpectatorMultipliers))
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,53 @@
+package matches
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/google/uuid"
+)
+
+func TestChatChannel(t *testing.T) {
+	white := uuid.New()
+	spectator := uuid.New()
+
+	match := &Match{IsOnline: true}
+	match.Online.Players = map[string]components.OnlinePlayerStruct{
+		"white": {ID: white},
+		"black": {},
+	}
+	match.AddSpectator(spectator, 80)
+
+	tests := []struct {
+		name    string
+		channel string
+		want    []uuid.UUID
+	}{
+		{
+			name:    "Players channel skips the empty seat",
+			channel: chat.ChannelPlayers,
+			want:    []uuid.UUID{white},
+		},
+		{
+			name:    "Spectators channel",
+			channel: chat.ChannelSpectators,
+			want:    []uuid.UUID{spectator},
+		},
+		{
+			name:    "Unknown channel",
+			channel: "lobby",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := match.ChatChannel(tt.channel)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ChatChannel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
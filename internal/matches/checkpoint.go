@@ -0,0 +1,168 @@
+package matches
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+)
+
+// Checkpoint is what's kept of a game in progress so it can be picked up
+// again after a restart. Castling rights live in the pieces' Moved flags.
+// Connections, premoves, a pending draw offer or takeback request and the
+// positions kept for takebacks (History) aren't kept: a restored game starts
+// without them, and the moves played before the restart can't be taken back.
+type Checkpoint struct {
+	Pieces                map[string]components.Piece
+	CoordinateMultiplier  int
+	IsWhiteTurn           bool
+	IsWhiteUnderCheck     bool
+	IsBlackUnderCheck     bool
+	TilesUnderAttack      []string
+	WhiteTimer            int
+	BlackTimer            int
+	TurnStartTimer        int
+	FullTime              int
+	Addition              int
+	Rated                 bool
+	AllMoves              []string
+	PiecesSnapshot        []map[string]components.Piece
+	MatchId               int32
+	MovesSinceLastCapture int
+	PossibleEnPessant     string
+	TakenPiecesWhite      []string
+	TakenPiecesBlack      []string
+	IsOnline              bool
+	Players               map[string]components.OnlinePlayerStruct
+	LastPosition          map[string]components.Piece
+	UciMoves              []string
+	SanMoves              []string
+	LastMove              MoveCommand
+	LastDrawOffers        map[string]int
+}
+
+// CheckpointTTL is how long a checkpoint nobody played on is kept. A local
+// game that's walked away from never ends, so nothing else drops it.
+const CheckpointTTL = 24 * time.Hour
+
+// Durable reports whether the game under key is worth a checkpoint: local
+// and online games are, the shared board and replays aren't.
+func Durable(key string) bool {
+	return strings.HasPrefix(key, localPrefix) || strings.HasPrefix(key, "online:")
+}
+
+// Checkpointed reports whether the match under key gets checkpoints. A local
+// game without a stored match belongs to nobody signed in, so there's no one
+// to pick it up again.
+func (m *Match) Checkpointed(key string) bool {
+	if strings.HasPrefix(key, localPrefix) {
+		return m.MatchId != 0
+	}
+
+	return Durable(key)
+}
+
+// CheckpointExpired reports whether a checkpoint last saved at savedAt is
+// too old to be picked up at now.
+func CheckpointExpired(savedAt, now time.Time) bool {
+	return now.Sub(savedAt) > CheckpointTTL
+}
+
+// ChargeRunningTurn takes the time the player on move has spent on the turn
+// so far off their clock, including the seconds their page hasn't ticked yet.
+// The running turn is saved before the server stops, or its time would be
+// handed back on restore.
+func (m *Match) ChargeRunningTurn(now time.Time) {
+	if m.TurnStarted.IsZero() || m.Result != "" {
+		return
+	}
+
+	left := m.TurnStartTimer - int(now.Sub(m.TurnStarted)/time.Second)
+	if m.IsWhiteTurn {
+		m.WhiteTimer = min(m.WhiteTimer, left)
+	} else {
+		m.BlackTimer = min(m.BlackTimer, left)
+	}
+}
+
+func (m *Match) Checkpoint() Checkpoint {
+	return Checkpoint{
+		Pieces:                maps.Clone(m.Pieces),
+		CoordinateMultiplier:  m.CoordinateMultiplier,
+		IsWhiteTurn:           m.IsWhiteTurn,
+		IsWhiteUnderCheck:     m.IsWhiteUnderCheck,
+		IsBlackUnderCheck:     m.IsBlackUnderCheck,
+		TilesUnderAttack:      slices.Clone(m.TilesUnderAttack),
+		WhiteTimer:            m.WhiteTimer,
+		BlackTimer:            m.BlackTimer,
+		TurnStartTimer:        m.TurnStartTimer,
+		FullTime:              m.FullTime,
+		Addition:              m.Addition,
+		Rated:                 m.Rated,
+		AllMoves:              slices.Clone(m.AllMoves),
+		PiecesSnapshot:        slices.Clone(m.PiecesSnapshot),
+		MatchId:               m.MatchId,
+		MovesSinceLastCapture: m.MovesSinceLastCapture,
+		PossibleEnPessant:     m.PossibleEnPessant,
+		TakenPiecesWhite:      slices.Clone(m.TakenPiecesWhite),
+		TakenPiecesBlack:      slices.Clone(m.TakenPiecesBlack),
+		IsOnline:              m.IsOnline,
+		Players:               maps.Clone(m.Online.Players),
+		LastPosition:          maps.Clone(m.LastPosition),
+		UciMoves:              slices.Clone(m.UciMoves),
+		SanMoves:              slices.Clone(m.SanMoves),
+		LastMove:              m.LastMove,
+		LastDrawOffers:        maps.Clone(m.LastDrawOffers),
+	}
+}
+
+// Restore rebuilds the match from the checkpoint. The clocks pick up from
+// where they were saved, so the time the server was down isn't charged to
+// the player on move. The online connections are left for the caller.
+func (c Checkpoint) Restore(now time.Time) Match {
+	match := Match{
+		Board:                 MakeBoard(),
+		Pieces:                c.Pieces,
+		CoordinateMultiplier:  c.CoordinateMultiplier,
+		IsWhiteTurn:           c.IsWhiteTurn,
+		IsWhiteUnderCheck:     c.IsWhiteUnderCheck,
+		IsBlackUnderCheck:     c.IsBlackUnderCheck,
+		TilesUnderAttack:      c.TilesUnderAttack,
+		WhiteTimer:            c.WhiteTimer,
+		BlackTimer:            c.BlackTimer,
+		TurnStartTimer:        c.TurnStartTimer,
+		TurnStarted:           now,
+		FullTime:              c.FullTime,
+		Addition:              c.Addition,
+		Rated:                 c.Rated,
+		AllMoves:              c.AllMoves,
+		PiecesSnapshot:        c.PiecesSnapshot,
+		MatchId:               c.MatchId,
+		MovesSinceLastCapture: c.MovesSinceLastCapture,
+		PossibleEnPessant:     c.PossibleEnPessant,
+		TakenPiecesWhite:      c.TakenPiecesWhite,
+		TakenPiecesBlack:      c.TakenPiecesBlack,
+		IsOnline:              c.IsOnline,
+		LastPosition:          c.LastPosition,
+		UciMoves:              c.UciMoves,
+		SanMoves:              c.SanMoves,
+		LastMove:              c.LastMove,
+		LastDrawOffers:        c.LastDrawOffers,
+	}
+	if match.Pieces == nil {
+		match.Pieces = make(map[string]components.Piece)
+	}
+	if match.AllMoves == nil {
+		match.AllMoves = []string{}
+	}
+	if c.IsOnline {
+		match.Online.Players = c.Players
+	}
+
+	match.FillBoard()
+	match.UpdateCoordinates(match.CoordinateMultiplier)
+
+	return match
+}
@@ -0,0 +1,247 @@
+package matches
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestCheckpointRestore(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	tests := []struct {
+		name     string
+		isOnline bool
+		moves    [][2]string
+	}{
+		{
+			name: "Local game before the first move",
+		},
+		{
+			name:  "Local game with a capture",
+			moves: [][2]string{{"2e", "4e"}, {"7d", "5d"}, {"4e", "5d"}},
+		},
+		{
+			name:     "Online game with a king moved",
+			isOnline: true,
+			moves:    [][2]string{{"2e", "4e"}, {"7e", "5e"}, {"1e", "2e"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := getMockNotationMatch()
+			match.CoordinateMultiplier = 60
+			match.FullTime = 600
+			match.MatchId = 7
+			match.IsOnline = tt.isOnline
+			if tt.isOnline {
+				match.Online.Players = map[string]components.OnlinePlayerStruct{
+					"white": {ID: white, Pieces: "white"},
+					"black": {ID: black, Pieces: "black"},
+				}
+			}
+			for _, move := range tt.moves {
+				playMove(&match, move[0], move[1])
+			}
+			match.WhiteTimer = 412
+			match.BlackTimer = 377
+			match.TurnStartTimer = 380
+
+			data, err := json.Marshal(match.Checkpoint())
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var checkpoint Checkpoint
+			err = json.Unmarshal(data, &checkpoint)
+			if err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			now := time.Now()
+			restored := checkpoint.Restore(now)
+
+			if restored.FEN() != match.FEN() {
+				t.Errorf("Restore() FEN = %v, want %v", restored.FEN(), match.FEN())
+			}
+			for tile, square := range match.Board {
+				got := restored.Board[tile]
+				if got.Piece.Name != square.Piece.Name || got.Coordinates != [2]int{square.CoordinatePosition[0] * 60, square.CoordinatePosition[1] * 60} {
+					t.Errorf("Restore() square %v = %+v, want %+v", tile, got, square)
+				}
+			}
+			if restored.WhiteTimer != 412 || restored.BlackTimer != 377 || restored.TurnStartTimer != 380 {
+				t.Errorf("Restore() timers = %v/%v/%v, want 412/377/380", restored.WhiteTimer, restored.BlackTimer, restored.TurnStartTimer)
+			}
+			if !restored.TurnStarted.Equal(now) {
+				t.Errorf("Restore() TurnStarted = %v, want %v", restored.TurnStarted, now)
+			}
+			if !slices.Equal(restored.AllMoves, match.AllMoves) || !slices.Equal(restored.TakenPiecesWhite, match.TakenPiecesWhite) {
+				t.Errorf("Restore() moves = %v taken %v, want %v taken %v", restored.AllMoves, restored.TakenPiecesWhite, match.AllMoves, match.TakenPiecesWhite)
+			}
+			if restored.IsPlayer(white) != tt.isOnline || restored.IsPlayer(black) != tt.isOnline {
+				t.Errorf("Restore() players = %v, want seated %v", restored.Online.Players, tt.isOnline)
+			}
+		})
+	}
+}
+
+func TestDurable(t *testing.T) {
+	tests := []struct {
+		game string
+		want bool
+	}{
+		{game: LocalGameKey("abc123"), want: true},
+		{game: "online:abc123", want: true},
+		{game: "initial", want: false},
+		{game: ReplayKey("12", "abc123"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.game, func(t *testing.T) {
+			if got := Durable(tt.game); got != tt.want {
+				t.Errorf("Durable(%v) = %v, want %v", tt.game, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointed(t *testing.T) {
+	tests := []struct {
+		name    string
+		game    string
+		matchId int32
+		want    bool
+	}{
+		{name: "Local game with a stored match", game: LocalGameKey("abc123"), matchId: 7, want: true},
+		{name: "Local game nobody is signed in to", game: LocalGameKey("abc123"), want: false},
+		{name: "Online game", game: "online:abc123", matchId: 7, want: true},
+		{name: "Shared board", game: "initial", want: false},
+		{name: "Replay", game: ReplayKey("12", "abc123"), matchId: 12, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{MatchId: tt.matchId}
+			if got := match.Checkpointed(tt.game); got != tt.want {
+				t.Errorf("Checkpointed(%v) = %v, want %v", tt.game, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointExpired(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		savedAt time.Time
+		want    bool
+	}{
+		{name: "Saved just now", savedAt: now, want: false},
+		{name: "Saved before a short restart", savedAt: now.Add(-10 * time.Minute), want: false},
+		{name: "Saved right at the limit", savedAt: now.Add(-CheckpointTTL), want: false},
+		{name: "Walked away from", savedAt: now.Add(-CheckpointTTL - time.Minute), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckpointExpired(tt.savedAt, now); got != tt.want {
+				t.Errorf("CheckpointExpired(%v) = %v, want %v", tt.savedAt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointDropsPendingRequests(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	match := getMockNotationMatch()
+	match.IsOnline = true
+	match.Online.Players = map[string]components.OnlinePlayerStruct{
+		"white": {ID: white, Pieces: "white"},
+		"black": {ID: black, Pieces: "black"},
+	}
+	playMove(&match, "2e", "4e")
+	match.History = append(match.History, Position{})
+	match.DrawOffer = DrawOffer{By: "white", Ply: 1}
+	match.Online.TakebackBy = white
+	match.Online.Premoves = map[string][]Premove{"white": {{From: "2d", To: "4d"}}}
+	match.Online.PremoveFrom = map[string]string{"white": "2d"}
+
+	data, err := json.Marshal(match.Checkpoint())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var checkpoint Checkpoint
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	restored := checkpoint.Restore(time.Now())
+
+	if restored.DrawOffer != (DrawOffer{}) {
+		t.Errorf("Restore() DrawOffer = %+v, want none", restored.DrawOffer)
+	}
+	if restored.Online.TakebackBy != uuid.Nil {
+		t.Errorf("Restore() TakebackBy = %v, want none", restored.Online.TakebackBy)
+	}
+	if len(restored.Online.Premoves) != 0 || len(restored.Online.PremoveFrom) != 0 {
+		t.Errorf("Restore() premoves = %v from %v, want none", restored.Online.Premoves, restored.Online.PremoveFrom)
+	}
+	if len(restored.History) != 0 {
+		t.Errorf("Restore() History = %v positions, want none", len(restored.History))
+	}
+	// The move played before the restart can't be taken back.
+	if err := restored.RequestTakeback(black); !errors.Is(err, ErrNoTakeback) {
+		t.Errorf("RequestTakeback() error = %v, want %v", err, ErrNoTakeback)
+	}
+}
+
+func TestChargeRunningTurn(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		isWhiteTurn bool
+		started     time.Time
+		result      string
+		wantWhite   int
+		wantBlack   int
+	}{
+		{name: "White thinking past the last tick", isWhiteTurn: true, started: now.Add(-12500 * time.Millisecond), wantWhite: 288, wantBlack: 250},
+		{name: "Black thinking past the last tick", started: now.Add(-12500 * time.Millisecond), wantWhite: 290, wantBlack: 238},
+		{name: "Ticks already ahead of the wall clock", isWhiteTurn: true, started: now.Add(-5 * time.Second), wantWhite: 290, wantBlack: 250},
+		{name: "Turn timer never started", isWhiteTurn: true, wantWhite: 290, wantBlack: 250},
+		{name: "Game already over", isWhiteTurn: true, started: now.Add(-time.Minute), result: "1-0", wantWhite: 290, wantBlack: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{
+				IsWhiteTurn:    tt.isWhiteTurn,
+				WhiteTimer:     290,
+				BlackTimer:     250,
+				TurnStartTimer: 300,
+				TurnStarted:    tt.started,
+				Result:         tt.result,
+			}
+			if !tt.isWhiteTurn {
+				match.TurnStartTimer = 250
+			}
+
+			match.ChargeRunningTurn(now)
+
+			if match.WhiteTimer != tt.wantWhite || match.BlackTimer != tt.wantBlack {
+				t.Errorf("ChargeRunningTurn() timers = %v/%v, want %v/%v", match.WhiteTimer, match.BlackTimer, tt.wantWhite, tt.wantBlack)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package matches
+
+import (
+	"errors"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDuplicateMove = errors.New("move was already played")
+	ErrMatchOver     = errors.New("the game is over")
+	ErrNotYourTurn   = errors.New("it's not your turn")
+	ErrStaleMove     = errors.New("the position changed since the move was made")
+	ErrNotYourPiece  = errors.New("no piece of the player to move on that square")
+	ErrNotSelected   = errors.New("the piece to move isn't selected")
+	ErrNoPromotion   = errors.New("no pawn is waiting to be promoted there")
+)
+
+// MoveCommand is a player asking to move the piece on From to To, in the
+// position after Ply half moves. A promotion names the new piece and has the
+// pawn's square as both From and To.
+type MoveCommand struct {
+	Player    uuid.UUID
+	Ply       int
+	From      string
+	To        string
+	Promotion string
+}
+
+// Authorize checks that the command can be played in the current position.
+// A retry of the move that was just played gets ErrDuplicateMove, so it can
+// be acknowledged without playing it twice.
+func (m *Match) Authorize(cmd MoveCommand) error {
+	if m.LastMove.To != "" && cmd == m.LastMove {
+		return ErrDuplicateMove
+	}
+
+	if m.Result != "" {
+		return ErrMatchOver
+	}
+
+	if m.IsOnline {
+		if !m.IsPlayer(cmd.Player) {
+			return ErrNotAPlayer
+		}
+		if m.Online.Players[m.colorToMove()].ID != cmd.Player {
+			return ErrNotYourTurn
+		}
+	}
+
+	if cmd.Ply != len(m.AllMoves) {
+		return ErrStaleMove
+	}
+
+	if cmd.Promotion == "" && (cmd.From == "" || cmd.From != m.SelectedPiece.Tile) {
+		return ErrNotSelected
+	}
+
+	piece := m.Board[cmd.From].Piece
+	if piece.Name == "" || piece.IsWhite != m.IsWhiteTurn {
+		return ErrNotYourPiece
+	}
+
+	if cmd.Promotion != "" {
+		lastRank := "8"
+		if !piece.IsWhite {
+			lastRank = "1"
+		}
+		if !piece.IsPawn || cmd.From != cmd.To || cmd.From[:1] != lastRank {
+			return ErrNoPromotion
+		}
+	}
+
+	return nil
+}
+
+// RememberMove keeps the command if it was played, so a retry of it is
+// recognized, and the position before it, so it can be taken back.
+func (m *Match) RememberMove(cmd MoveCommand, before Position) {
+	played := len(m.AllMoves) == cmd.Ply+1
+	if cmd.Promotion != "" {
+		played = !m.Board[cmd.From].Piece.IsPawn
+	}
+
+	if !played {
+		return
+	}
+
+	if cmd.Promotion == "" {
+		m.History = append(m.History, before)
+	}
+	m.LastMove = cmd
+}
+
+// MoveState is what a freshly loaded board has to send with its first move.
+func (m *Match) MoveState() components.MoveStateStruct {
+	return components.MoveStateStruct{Ply: len(m.AllMoves), From: m.SelectedPiece.Tile}
+}
@@ -0,0 +1,158 @@
+package matches
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestAuthorize(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	onlineMatch := func() Match {
+		match := getMockNotationMatch()
+		match.IsOnline = true
+		match.Online.Players = map[string]components.OnlinePlayerStruct{
+			"white": {ID: white},
+			"black": {ID: black},
+		}
+		match.SelectedPiece = match.Board["2e"].Piece
+
+		return match
+	}
+
+	tests := []struct {
+		name    string
+		match   func() Match
+		cmd     MoveCommand
+		wantErr error
+	}{
+		{
+			name:  "Selected piece on its turn",
+			match: onlineMatch,
+			cmd:   MoveCommand{Player: white, From: "2e", To: "4e"},
+		},
+		{
+			name:    "Not a player",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: uuid.New(), From: "2e", To: "4e"},
+			wantErr: ErrNotAPlayer,
+		},
+		{
+			name:    "Out of turn",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: black, From: "7e", To: "5e"},
+			wantErr: ErrNotYourTurn,
+		},
+		{
+			name:    "Stale ply",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: white, Ply: 2, From: "2e", To: "4e"},
+			wantErr: ErrStaleMove,
+		},
+		{
+			name: "Opponent's piece",
+			match: func() Match {
+				match := onlineMatch()
+				match.SelectedPiece = match.Board["7e"].Piece
+				return match
+			},
+			cmd:     MoveCommand{Player: white, From: "7e", To: "5e"},
+			wantErr: ErrNotYourPiece,
+		},
+		{
+			name:    "Nothing selected",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: white, To: "4e"},
+			wantErr: ErrNotSelected,
+		},
+		{
+			name:    "Piece that isn't selected",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: white, From: "2d", To: "4d"},
+			wantErr: ErrNotSelected,
+		},
+		{
+			name: "Retry of the last move",
+			match: func() Match {
+				match := onlineMatch()
+				movePiece(&match, "2e", "4e")
+				match.AllMoves = append(match.AllMoves, "4e")
+				match.IsWhiteTurn = false
+				match.LastMove = MoveCommand{Player: white, From: "2e", To: "4e"}
+				return match
+			},
+			cmd:     MoveCommand{Player: white, From: "2e", To: "4e"},
+			wantErr: ErrDuplicateMove,
+		},
+		{
+			name: "Game over",
+			match: func() Match {
+				match := onlineMatch()
+				match.Result = "0-1"
+				return match
+			},
+			cmd:     MoveCommand{Player: white, From: "2e", To: "4e"},
+			wantErr: ErrMatchOver,
+		},
+		{
+			name: "Promotion of a pawn on the last rank",
+			match: func() Match {
+				match := onlineMatch()
+				movePiece(&match, "8a", "6a")
+				movePiece(&match, "2a", "8a")
+				match.AllMoves = append(match.AllMoves, "8a")
+				match.SelectedPiece = components.Piece{}
+				return match
+			},
+			cmd: MoveCommand{Player: white, Ply: 1, From: "8a", To: "8a", Promotion: "white_queen"},
+		},
+		{
+			name:    "Promotion of a pawn that isn't there yet",
+			match:   onlineMatch,
+			cmd:     MoveCommand{Player: white, From: "2e", To: "2e", Promotion: "white_queen"},
+			wantErr: ErrNoPromotion,
+		},
+		{
+			name: "Local game doesn't check the player",
+			match: func() Match {
+				match := getMockNotationMatch()
+				match.SelectedPiece = match.Board["2e"].Piece
+				return match
+			},
+			cmd: MoveCommand{From: "2e", To: "4e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := tt.match()
+			if err := match.Authorize(tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRememberMove(t *testing.T) {
+	match := getMockNotationMatch()
+	cmd := MoveCommand{From: "2e", To: "4e"}
+
+	match.RememberMove(cmd, match.Snapshot())
+	if match.LastMove == cmd {
+		t.Fatalf("RememberMove() kept a move that wasn't played")
+	}
+
+	before := match.Snapshot()
+	match.AllMoves = append(match.AllMoves, "4e")
+	match.RememberMove(cmd, before)
+	if match.LastMove != cmd {
+		t.Errorf("LastMove = %+v, want %+v", match.LastMove, cmd)
+	}
+	if len(match.History) != 1 {
+		t.Errorf("len(History) = %v, want 1", len(match.History))
+	}
+}
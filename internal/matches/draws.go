@@ -0,0 +1,268 @@
+package matches
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// A draw can be claimed once a position comes up for the third time or after
+// fifty moves without a capture or a pawn move. At five times and seventy-five
+// moves the game is drawn without a claim.
+const (
+	ClaimRepetitions = 3
+	AutoRepetitions  = 5
+	ClaimHalfMoves   = 100
+	AutoHalfMoves    = 150
+)
+
+var (
+	ErrDrawOfferPending = errors.New("a draw is already offered")
+	ErrDrawOfferTooSoon = errors.New("only one draw offer per move")
+	ErrNoDrawOffer      = errors.New("there is no draw offer to answer")
+	ErrNoDrawClaim      = errors.New("there is no draw to claim")
+)
+
+// DrawOffer is a draw offered by one color. Ply is the move the offer is
+// written next to in the move history: the offerer's last move, or the one
+// they're about to play if it's their turn.
+type DrawOffer struct {
+	By  string
+	Ply int
+}
+
+// offerColor is the color a player offers or claims a draw for. Both sides
+// of a local game share the board, so there it's the side to move.
+func (m *Match) offerColor(player uuid.UUID) (string, bool) {
+	if !m.IsOnline {
+		return m.colorToMove(), true
+	}
+
+	return m.playerColor(player)
+}
+
+// answerColor is the color a player answers a draw offer for.
+func (m *Match) answerColor(player uuid.UUID) (string, bool) {
+	if !m.IsOnline {
+		return otherColor(m.DrawOffer.By), true
+	}
+
+	return m.playerColor(player)
+}
+
+func (m *Match) playerColor(player uuid.UUID) (string, bool) {
+	for color, p := range m.Online.Players {
+		if player != uuid.Nil && p.ID == player {
+			return color, true
+		}
+	}
+
+	return "", false
+}
+
+func otherColor(color string) string {
+	if color == "white" {
+		return "black"
+	}
+
+	return "white"
+}
+
+// OfferDraw offers a draw for the player, or agrees to it if the opponent
+// already offered one, which it reports as true.
+func (m *Match) OfferDraw(player uuid.UUID) (bool, error) {
+	color, ok := m.offerColor(player)
+	if !ok {
+		return false, ErrNotAPlayer
+	}
+	if m.Result != "" {
+		return false, ErrMatchOver
+	}
+
+	if m.DrawOffer.By != "" {
+		if m.DrawOffer.By == color {
+			return false, ErrDrawOfferPending
+		}
+
+		m.DrawOffer = DrawOffer{}
+		m.PublishGameEnd("1-1", TerminationAgreement)
+
+		return true, nil
+	}
+
+	if last, offered := m.LastDrawOffers[color]; offered && len(m.AllMoves) < last+2 {
+		return false, ErrDrawOfferTooSoon
+	}
+
+	ply := len(m.AllMoves)
+	if color != m.colorToMove() {
+		ply--
+	}
+
+	m.DrawOffer = DrawOffer{By: color, Ply: ply}
+	if m.LastDrawOffers == nil {
+		m.LastDrawOffers = make(map[string]int)
+	}
+	m.LastDrawOffers[color] = len(m.AllMoves)
+	m.Publish(protocol.NewDrawOffer(color))
+
+	return false, nil
+}
+
+// DrawOfferedOnLastMove reports whether the pending offer goes with the move
+// that was just played.
+func (m *Match) DrawOfferedOnLastMove() bool {
+	return m.DrawOffer.By != "" && m.DrawOffer.Ply == len(m.AllMoves)-1
+}
+
+func (m *Match) AcceptDraw(player uuid.UUID) error {
+	color, ok := m.answerColor(player)
+	if !ok || m.DrawOffer.By == "" || m.DrawOffer.By == color {
+		return ErrNoDrawOffer
+	}
+	if m.Result != "" {
+		return ErrMatchOver
+	}
+
+	m.DrawOffer = DrawOffer{}
+	m.PublishGameEnd("1-1", TerminationAgreement)
+
+	return nil
+}
+
+func (m *Match) DeclineDraw(player uuid.UUID) error {
+	color, ok := m.answerColor(player)
+	if !ok || m.DrawOffer.By == "" || m.DrawOffer.By == color {
+		return ErrNoDrawOffer
+	}
+
+	m.DrawOffer = DrawOffer{}
+
+	return nil
+}
+
+// ClaimableDraw is the termination a draw could be claimed with right now,
+// or empty if there is none.
+func (m *Match) ClaimableDraw() string {
+	if m.Result != "" {
+		return ""
+	}
+	if m.repetitions() >= ClaimRepetitions {
+		return TerminationRepetition
+	}
+	if m.MovesSinceLastCapture >= ClaimHalfMoves {
+		return TerminationFiftyMoves
+	}
+
+	return ""
+}
+
+// ClaimDraw ends the game in a draw if the player to move can claim one.
+func (m *Match) ClaimDraw(player uuid.UUID) (string, error) {
+	if color, ok := m.offerColor(player); !ok || color != m.colorToMove() {
+		return "", ErrNotYourTurn
+	}
+
+	termination := m.ClaimableDraw()
+	if termination == "" {
+		return "", ErrNoDrawClaim
+	}
+
+	m.DrawOffer = DrawOffer{}
+	m.PublishGameEnd("1-1", termination)
+
+	return termination, nil
+}
+
+// countPly keeps what the draw rules need after a ply, given the pieces
+// before it. Captures and pawn moves restart the fifty-move count, and like
+// castling they make every earlier position unreachable.
+func (m *Match) countPly(san string, before map[string]components.Piece) {
+	switch {
+	case strings.Contains(san, "x") || san != "" && san[0] >= 'a' && san[0] <= 'h':
+		m.MovesSinceLastCapture = 0
+		m.PiecesSnapshot = nil
+	case strings.HasPrefix(san, "O-O"):
+		m.MovesSinceLastCapture++
+		m.PiecesSnapshot = nil
+	default:
+		m.MovesSinceLastCapture++
+		if len(m.PiecesSnapshot) == 0 {
+			m.PiecesSnapshot = append(m.PiecesSnapshot, before)
+		}
+	}
+
+	m.PiecesSnapshot = append(m.PiecesSnapshot, maps.Clone(m.Pieces))
+}
+
+// repetitions is how many times the current position came up with the same
+// side to move.
+func (m *Match) repetitions() int {
+	n := len(m.PiecesSnapshot)
+	if n == 0 {
+		return 0
+	}
+
+	latest := m.PiecesSnapshot[n-1]
+	count := 1
+	for i := n - 3; i >= 0; i -= 2 {
+		if samePosition(latest, m.PiecesSnapshot[i]) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// updateDraws runs once the mover's ply is done. The pending offer expires if
+// it was the offerer's opponent who moved, and the side to move gets the
+// claim draw button when it can claim one.
+func (m *Match) updateDraws(w io.Writer, mover string) {
+	var msg strings.Builder
+
+	if m.DrawOffer.By != "" && m.DrawOffer.By != mover {
+		m.DrawOffer = DrawOffer{}
+
+		cleared, err := utils.TemplString(components.DrawOfferBox("", false))
+		if err != nil {
+			responses.LogError("couldn't render draw offer", err)
+			return
+		}
+		msg.WriteString(cleared)
+	}
+
+	noClaim, err := utils.TemplString(components.ClaimDrawButton(""))
+	if err != nil {
+		responses.LogError("couldn't render claim draw button", err)
+		return
+	}
+	claim, err := utils.TemplString(components.ClaimDrawButton(m.ClaimableDraw()))
+	if err != nil {
+		responses.LogError("couldn't render claim draw button", err)
+		return
+	}
+
+	if !m.IsOnline {
+		_, err = fmt.Fprint(w, msg.String()+claim)
+		if err != nil {
+			responses.LogError("couldn't write to page", err)
+		}
+		return
+	}
+
+	for color, player := range m.Online.Players {
+		if color == m.colorToMove() {
+			m.Online.Hub.Send(player.ID, msg.String()+claim)
+		} else {
+			m.Online.Hub.Send(player.ID, msg.String()+noClaim)
+		}
+	}
+}
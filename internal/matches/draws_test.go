@@ -0,0 +1,225 @@
+package matches
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+// endTurn plays a move and ends the turn the way the handlers do, returning
+// what was written to the page.
+func endTurn(m *Match, from, to string) string {
+	w := httptest.NewRecorder()
+	movePiece(m, from, to)
+	m.AllMoves = append(m.AllMoves, to)
+	m.EndTurn(w)
+
+	return w.Body.String()
+}
+
+func TestOfferDraw(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	onlineMatch := func(moves ...[2]string) Match {
+		match := getMockNotationMatch()
+		match.IsOnline = true
+		match.Online.Players = map[string]components.OnlinePlayerStruct{
+			"white": {ID: white},
+			"black": {ID: black},
+		}
+		for _, move := range moves {
+			playMove(&match, move[0], move[1])
+		}
+
+		return match
+	}
+
+	localMatch := func(moves ...[2]string) Match {
+		match := getMockNotationMatch()
+		for _, move := range moves {
+			playMove(&match, move[0], move[1])
+		}
+
+		return match
+	}
+
+	tests := []struct {
+		name         string
+		match        Match
+		before       func(m *Match)
+		player       uuid.UUID
+		wantErr      error
+		wantAccepted bool
+		wantOffer    DrawOffer
+	}{
+		{
+			name:      "On the own turn",
+			match:     onlineMatch(),
+			player:    white,
+			wantOffer: DrawOffer{By: "white", Ply: 0},
+		},
+		{
+			name:      "Right after the own move",
+			match:     onlineMatch([2]string{"2e", "4e"}),
+			player:    white,
+			wantOffer: DrawOffer{By: "white", Ply: 0},
+		},
+		{
+			name:    "Already offered",
+			match:   onlineMatch(),
+			before:  func(m *Match) { m.OfferDraw(white) },
+			player:  white,
+			wantErr: ErrDrawOfferPending,
+		},
+		{
+			name:         "Opponent offered first",
+			match:        onlineMatch(),
+			before:       func(m *Match) { m.OfferDraw(white) },
+			player:       black,
+			wantAccepted: true,
+		},
+		{
+			name:  "Again on the same move",
+			match: onlineMatch(),
+			before: func(m *Match) {
+				m.OfferDraw(white)
+				m.DeclineDraw(black)
+			},
+			player:  white,
+			wantErr: ErrDrawOfferTooSoon,
+		},
+		{
+			name:  "Again a move later",
+			match: onlineMatch([2]string{"2e", "4e"}, [2]string{"7e", "5e"}),
+			before: func(m *Match) {
+				m.LastDrawOffers = map[string]int{"white": 0}
+			},
+			player:    white,
+			wantOffer: DrawOffer{By: "white", Ply: 2},
+		},
+		{
+			name:    "Not a player",
+			match:   onlineMatch(),
+			player:  uuid.New(),
+			wantErr: ErrNotAPlayer,
+		},
+		{
+			name:      "Local game offers for the side to move",
+			match:     localMatch([2]string{"2e", "4e"}),
+			wantOffer: DrawOffer{By: "black", Ply: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := tt.match
+			if tt.before != nil {
+				tt.before(&match)
+			}
+
+			accepted, err := match.OfferDraw(tt.player)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OfferDraw() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if accepted != tt.wantAccepted {
+				t.Errorf("OfferDraw() accepted = %v, want %v", accepted, tt.wantAccepted)
+			}
+			if match.DrawOffer != tt.wantOffer {
+				t.Errorf("OfferDraw() offer = %+v, want %+v", match.DrawOffer, tt.wantOffer)
+			}
+			if accepted && match.Result != "1-1" {
+				t.Errorf("OfferDraw() result = %q, want 1-1", match.Result)
+			}
+		})
+	}
+}
+
+func TestDrawOfferExpires(t *testing.T) {
+	match := getMockNotationMatch()
+
+	if _, err := match.OfferDraw(uuid.Nil); err != nil {
+		t.Fatalf("OfferDraw() error = %v", err)
+	}
+
+	endTurn(&match, "2e", "4e")
+	if match.DrawOffer.By != "white" {
+		t.Fatalf("offer = %+v after the offerer moved, want it pending", match.DrawOffer)
+	}
+	if !match.DrawOfferedOnLastMove() {
+		t.Errorf("DrawOfferedOnLastMove() = false, want true")
+	}
+
+	page := endTurn(&match, "7e", "5e")
+	if match.DrawOffer.By != "" {
+		t.Errorf("offer = %+v after the opponent moved, want none", match.DrawOffer)
+	}
+	if !strings.Contains(page, `id="draw-offer"`) {
+		t.Errorf("expired offer wasn't cleared on the page: %q", page)
+	}
+}
+
+func TestClaimableDraw(t *testing.T) {
+	shuffle := [][2]string{{"1g", "3f"}, {"8g", "6f"}, {"3f", "1g"}, {"6f", "8g"}}
+
+	tests := []struct {
+		name                  string
+		movesSinceLastCapture int
+		moves                 [][2]string
+		want                  string
+	}{
+		{
+			name:  "Position repeated twice",
+			moves: shuffle,
+		},
+		{
+			name:  "Position repeated three times",
+			moves: append(append([][2]string{}, shuffle...), shuffle...),
+			want:  TerminationRepetition,
+		},
+		{
+			name:                  "Fifty moves",
+			movesSinceLastCapture: 99,
+			moves:                 [][2]string{{"1g", "3f"}},
+			want:                  TerminationFiftyMoves,
+		},
+		{
+			name:                  "Pawn move restarts the count",
+			movesSinceLastCapture: 99,
+			moves:                 [][2]string{{"2e", "4e"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := getMockNotationMatch()
+			match.MovesSinceLastCapture = tt.movesSinceLastCapture
+			for _, move := range tt.moves {
+				endTurn(&match, move[0], move[1])
+			}
+
+			if got := match.ClaimableDraw(); got != tt.want {
+				t.Fatalf("ClaimableDraw() = %q, want %q", got, tt.want)
+			}
+
+			termination, err := match.ClaimDraw(uuid.Nil)
+			if tt.want == "" {
+				if !errors.Is(err, ErrNoDrawClaim) {
+					t.Errorf("ClaimDraw() error = %v, want %v", err, ErrNoDrawClaim)
+				}
+				return
+			}
+			if err != nil || termination != tt.want || match.Result != "1-1" {
+				t.Errorf("ClaimDraw() = %q, %v with result %q, want %q and 1-1", termination, err, match.Result, tt.want)
+			}
+		})
+	}
+}
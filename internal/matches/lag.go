@@ -0,0 +1,82 @@
+package matches
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+)
+
+// MaxLagCompensation is the most a single move gets back on the clock for
+// the time it spent on its way to the server.
+const MaxLagCompensation = 500 * time.Millisecond
+
+// Lag is the player's measured connection lag, half the round trip of the
+// pings on their websocket.
+func (m *Match) Lag(color string) time.Duration {
+	if !m.IsOnline || m.Online.Hub == nil {
+		return 0
+	}
+	player, ok := m.Online.Players[color]
+	if !ok {
+		return 0
+	}
+	rtt, ok := m.Online.Hub.RoundTrip(player.ID)
+	if !ok {
+		return 0
+	}
+
+	return rtt / 2
+}
+
+// MoverLag is the lag of the player whose move is being played.
+func (m *Match) MoverLag() time.Duration {
+	return m.Lag(m.colorToMove())
+}
+
+// compensateLag gives the player back the time their move spent on the wire,
+// up to MaxLagCompensation. The clocks only count whole seconds, so the
+// credit adds up until it makes one.
+func (m *Match) compensateLag(color string, lag time.Duration) {
+	if !m.IsOnline || lag <= 0 {
+		return
+	}
+	if m.Online.LagCredit == nil {
+		m.Online.LagCredit = make(map[string]time.Duration)
+	}
+
+	credit := m.Online.LagCredit[color] + min(lag, MaxLagCompensation)
+	seconds := int(credit / time.Second)
+	m.Online.LagCredit[color] = credit - time.Duration(seconds)*time.Second
+
+	if color == "white" {
+		m.WhiteTimer += seconds
+	} else {
+		m.BlackTimer += seconds
+	}
+}
+
+// SendLag shows both players' lag in their panels.
+func (m *Match) SendLag(w io.Writer) {
+	if !m.IsOnline {
+		return
+	}
+
+	var msg strings.Builder
+	for _, color := range []string{"white", "black"} {
+		lag, err := utils.TemplString(components.PlayerLag(color, m.Lag(color)))
+		if err != nil {
+			responses.LogError("couldn't render lag", err)
+			return
+		}
+		msg.WriteString(lag)
+	}
+
+	err := m.SendMessage(w, msg.String(), [2][]int{})
+	if err != nil {
+		responses.LogError("couldn't send lag", err)
+	}
+}
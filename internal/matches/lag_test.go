@@ -0,0 +1,71 @@
+package matches
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompensateLag(t *testing.T) {
+	tests := []struct {
+		name       string
+		isOnline   bool
+		credit     time.Duration
+		lag        time.Duration
+		wantTimer  int
+		wantCredit time.Duration
+	}{
+		{
+			name:       "Credit below a second is carried",
+			isOnline:   true,
+			lag:        300 * time.Millisecond,
+			wantTimer:  60,
+			wantCredit: 300 * time.Millisecond,
+		},
+		{
+			name:       "Carried credit adds up to a second",
+			isOnline:   true,
+			credit:     800 * time.Millisecond,
+			lag:        300 * time.Millisecond,
+			wantTimer:  61,
+			wantCredit: 100 * time.Millisecond,
+		},
+		{
+			name:       "Lag over the cap is only partly credited",
+			isOnline:   true,
+			credit:     600 * time.Millisecond,
+			lag:        3 * time.Second,
+			wantTimer:  61,
+			wantCredit: 100 * time.Millisecond,
+		},
+		{
+			name:      "Local games aren't compensated",
+			lag:       300 * time.Millisecond,
+			wantTimer: 60,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Match{
+				IsOnline:   tt.isOnline,
+				WhiteTimer: 60,
+				BlackTimer: 60,
+			}
+			if tt.credit > 0 {
+				m.Online.LagCredit = map[string]time.Duration{"white": tt.credit}
+			}
+
+			m.compensateLag("white", tt.lag)
+
+			if m.WhiteTimer != tt.wantTimer {
+				t.Errorf("WhiteTimer = %v, want %v", m.WhiteTimer, tt.wantTimer)
+			}
+			if m.BlackTimer != 60 {
+				t.Errorf("BlackTimer = %v, want 60", m.BlackTimer)
+			}
+			if got := m.Online.LagCredit["white"]; got != tt.wantCredit {
+				t.Errorf("LagCredit = %v, want %v", got, tt.wantCredit)
+			}
+		})
+	}
+}
@@ -172,34 +172,6 @@ func checkForNotEnoughPieces(pieces map[string]components.Piece) bool {
 	return true
 }
 
-func checkForRepeatingMoves(match *Match) bool {
-	snapshots := match.PiecesSnapshot
-	n := len(snapshots)
-
-	if n < 3 {
-		return false
-	}
-
-	latest := snapshots[n-1]
-
-	count := 0
-	for i := 0; i < n-1; i += 2 {
-		if samePosition(latest, snapshots[i]) {
-			count++
-		}
-	}
-
-	if count >= 2 {
-		return true
-	}
-
-	if n > 10 {
-		match.PiecesSnapshot = snapshots[n-10:]
-	}
-
-	return false
-}
-
 func samePosition(a, b map[string]components.Piece) bool {
 	if len(a) != len(b) {
 		return false
@@ -215,3 +187,14 @@ func samePosition(a, b map[string]components.Piece) bool {
 	}
 	return true
 }
+
+func (m *Match) ClearSelection() {
+	if m.SelectedPiece.Name == "" {
+		return
+	}
+
+	square := m.Board[m.SelectedPiece.Tile]
+	square.Selected = false
+	m.Board[m.SelectedPiece.Tile] = square
+	m.SelectedPiece = components.Piece{}
+}
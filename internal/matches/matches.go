@@ -1,7 +1,7 @@
 package matches
 
 import (
-	"net/http"
+	"io"
 	"slices"
 	"strings"
 
@@ -297,7 +297,7 @@ func (m *Match) HandleChecksWhenKingMoves(currentSquareName string) bool {
 	return false
 }
 
-func (m *Match) HandleIfCheck(w http.ResponseWriter, r *http.Request, selected components.Piece) (bool, error) {
+func (m *Match) HandleIfCheck(w io.Writer, multiplier int, selected components.Piece) (bool, error) {
 	check, king, tilesUnderAttack := m.HandleCheckForCheck("", selected)
 	kingSquare := m.Board[king.Tile]
 	if check {
@@ -311,7 +311,7 @@ func (m *Match) HandleIfCheck(w http.ResponseWriter, r *http.Request, selected c
 			t := m.Board[tile]
 
 			if t.Piece.Name != "" {
-				err := responses.RespondWithNewPiece(w, r, t)
+				err := responses.RespondWithNewPiece(w, t, multiplier)
 
 				if err != nil {
 					return false, err
@@ -341,32 +341,6 @@ func (m *Match) CleanFillBoard(pieces map[string]components.Piece) {
 	}
 }
 
-func (m *Matches) GetMatch(key string) (Match, bool) {
-	match, ok := m.Matches[key]
-	return match, ok
-}
-
-func (m *Matches) SetMatch(key string, match Match) {
-	m.Matches[key] = match
-}
-
-func (m *Matches) GetInitialMatch() Match {
-	match := m.Matches["initial"]
-	return match
-}
-
-func (m *Matches) GetAllOnlineMatches() map[string]Match {
-	onlineMatches := make(map[string]Match)
-
-	for name, match := range m.Matches {
-		if match.IsOnline {
-			onlineMatches[name] = match
-		}
-	}
-
-	return onlineMatches
-}
-
 func (m *Match) IsOnlineMatch() (OnlineGame, bool) {
 	return m.Online, m.IsOnline
 }
@@ -1,6 +1,7 @@
 package matches
 
 import (
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -218,3 +219,60 @@ func TestNotEnoughPIeces(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeSpentOnTurn(t *testing.T) {
+	tests := []struct {
+		name       string
+		match      Match
+		wantResult int
+	}{
+		{
+			name:       "White spent time on the move",
+			match:      Match{IsWhiteTurn: true, WhiteTimer: 580, BlackTimer: 600, TurnStartTimer: 600},
+			wantResult: 20,
+		},
+		{
+			name:       "Black spent time on the move",
+			match:      Match{IsWhiteTurn: false, WhiteTimer: 580, BlackTimer: 550, TurnStartTimer: 595},
+			wantResult: 45,
+		},
+		{
+			name:       "Turn timer never started",
+			match:      Match{IsWhiteTurn: true, WhiteTimer: 580},
+			wantResult: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spent := tt.match.TimeSpentOnTurn()
+
+			if spent != tt.wantResult {
+				t.Errorf("TimeSpentOnTurn() spent = %v, want %v", spent, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestEndTurnStartsTurnTimer(t *testing.T) {
+	match := Match{
+		Pieces:         MakePieces(),
+		Board:          MakeBoard(),
+		IsWhiteTurn:    true,
+		WhiteTimer:     590,
+		BlackTimer:     600,
+		TurnStartTimer: 600,
+		Addition:       2,
+	}
+	match.FillBoard()
+
+	match.EndTurn(httptest.NewRecorder())
+
+	if match.TurnStartTimer != 600 {
+		t.Errorf("EndTurn() TurnStartTimer = %v, want %v", match.TurnStartTimer, 600)
+	}
+
+	if match.WhiteTimer != 592 {
+		t.Errorf("EndTurn() WhiteTimer = %v, want %v", match.WhiteTimer, 592)
+	}
+}
@@ -0,0 +1,217 @@
+package matches
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+)
+
+// StartFEN is the position every game starts from.
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var pieceLetters = map[string]string{
+	"king":   "K",
+	"queen":  "Q",
+	"rook":   "R",
+	"bishop": "B",
+	"knight": "N",
+	"pawn":   "P",
+}
+
+// SquareName turns a board tile like "4e" into the algebraic square "e4".
+func SquareName(tile string) string {
+	if len(tile) != 2 {
+		return tile
+	}
+
+	return string(tile[1]) + string(tile[0])
+}
+
+// TileName turns an algebraic square like "e4" into the board tile "4e".
+func TileName(square string) string {
+	if len(square) != 2 {
+		return square
+	}
+
+	return string(square[1]) + string(square[0])
+}
+
+func pieceLetter(piece components.Piece) string {
+	_, kind, _ := strings.Cut(piece.Image, "_")
+
+	return pieceLetters[kind]
+}
+
+func (m *Match) colorToMove() string {
+	if m.IsWhiteTurn {
+		return "white"
+	}
+
+	return "black"
+}
+
+func (m *Match) FEN() string {
+	var placement strings.Builder
+	for rowIdx, row := range rows {
+		empty := 0
+		for _, col := range cols {
+			piece := m.Board[fmt.Sprintf("%v%v", row, col)].Piece
+			letter := pieceLetter(piece)
+			if piece.Name == "" || letter == "" {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				fmt.Fprint(&placement, empty)
+				empty = 0
+			}
+			if !piece.IsWhite {
+				letter = strings.ToLower(letter)
+			}
+			placement.WriteString(letter)
+		}
+		if empty > 0 {
+			fmt.Fprint(&placement, empty)
+		}
+		if rowIdx < len(rows)-1 {
+			placement.WriteString("/")
+		}
+	}
+
+	turn := "w"
+	if !m.IsWhiteTurn {
+		turn = "b"
+	}
+
+	return fmt.Sprintf("%v %v %v %v %v %v",
+		placement.String(),
+		turn,
+		m.castlingRights(),
+		m.enPassantSquare(),
+		m.MovesSinceLastCapture,
+		len(m.AllMoves)/2+1,
+	)
+}
+
+func (m *Match) castlingRights() string {
+	unmoved := func(tile, image string) bool {
+		piece := m.Board[tile].Piece
+		return piece.Name != "" && piece.Image == image && !piece.Moved
+	}
+
+	var rights string
+	if unmoved("1e", "white_king") {
+		if unmoved("1h", "white_rook") {
+			rights += "K"
+		}
+		if unmoved("1a", "white_rook") {
+			rights += "Q"
+		}
+	}
+	if unmoved("8e", "black_king") {
+		if unmoved("8h", "black_rook") {
+			rights += "k"
+		}
+		if unmoved("8a", "black_rook") {
+			rights += "q"
+		}
+	}
+
+	if rights == "" {
+		return "-"
+	}
+
+	return rights
+}
+
+func (m *Match) enPassantSquare() string {
+	if len(m.UciMoves) == 0 || len(m.SanMoves) == 0 {
+		return "-"
+	}
+
+	last := m.UciMoves[len(m.UciMoves)-1]
+	san := m.SanMoves[len(m.SanMoves)-1]
+	if len(last) < 4 || !strings.ContainsAny(san[:1], "abcdefgh") {
+		return "-"
+	}
+
+	fromRank, toRank := last[1], last[3]
+	if last[0] != last[2] || (fromRank != '2' || toRank != '4') && (fromRank != '7' || toRank != '5') {
+		return "-"
+	}
+
+	return fmt.Sprintf("%c%c", last[0], (fromRank+toRank)/2)
+}
+
+// RecordMove works out the move that was just played by comparing the pieces
+// with where they stood when the previous turn ended. It must be called by the
+// player who moved, before the turn flips.
+func (m *Match) RecordMove() (protocol.Move, bool) {
+	previous := m.LastPosition
+	if previous == nil {
+		previous = MakePieces()
+	}
+	m.LastPosition = maps.Clone(m.Pieces)
+
+	var moved []components.Piece
+	var captured bool
+	for name, before := range previous {
+		after, ok := m.Pieces[name]
+		if !ok {
+			captured = true
+			continue
+		}
+		if after.Tile != before.Tile {
+			moved = append(moved, after)
+		}
+	}
+
+	if len(moved) == 0 {
+		return protocol.Move{}, false
+	}
+
+	piece := moved[0]
+	if len(moved) == 2 && pieceLetter(moved[1]) == "K" {
+		piece = moved[1]
+	}
+	from := SquareName(previous[piece.Name].Tile)
+	to := SquareName(piece.Tile)
+	uci := from + to
+
+	var san string
+	switch {
+	case len(moved) == 2 && to[0] == 'g':
+		san = "O-O"
+	case len(moved) == 2:
+		san = "O-O-O"
+	default:
+		letter := pieceLetter(previous[piece.Name])
+		if letter == "P" {
+			letter = ""
+			if captured {
+				letter = from[:1]
+			}
+		}
+		san = letter
+		if captured {
+			san += "x"
+		}
+		san += to
+		if promoted := pieceLetter(piece); promoted != pieceLetter(previous[piece.Name]) {
+			san += "=" + promoted
+			uci += strings.ToLower(promoted)
+		}
+	}
+
+	if m.IsWhiteTurn && m.IsBlackUnderCheck || !m.IsWhiteTurn && m.IsWhiteUnderCheck {
+		san += "+"
+	}
+
+	m.UciMoves = append(m.UciMoves, uci)
+	m.SanMoves = append(m.SanMoves, san)
+
+	return protocol.NewMove(len(m.UciMoves), san, uci, protocol.Clock{White: m.WhiteTimer, Black: m.BlackTimer}), true
+}
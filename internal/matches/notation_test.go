@@ -0,0 +1,171 @@
+package matches
+
+import (
+	"testing"
+)
+
+func getMockNotationMatch() Match {
+	match := Match{
+		Board:       MakeBoard(),
+		Pieces:      MakePieces(),
+		IsWhiteTurn: true,
+		WhiteTimer:  600,
+		BlackTimer:  600,
+	}
+	match.FillBoard()
+
+	return match
+}
+
+func movePiece(m *Match, from, to string) {
+	piece := m.Board[from].Piece
+	if captured := m.Board[to].Piece; captured.Name != "" {
+		delete(m.Pieces, captured.Name)
+	}
+
+	fromSquare := m.Board[from]
+	fromSquare.Piece.Name = ""
+	fromSquare.Piece.Image = ""
+	m.Board[from] = fromSquare
+
+	piece.Tile = to
+	piece.Moved = true
+	m.Pieces[piece.Name] = piece
+	toSquare := m.Board[to]
+	toSquare.Piece = piece
+	m.Board[to] = toSquare
+}
+
+func TestSquareAndTileNames(t *testing.T) {
+	tests := []struct {
+		tile   string
+		square string
+	}{
+		{tile: "4e", square: "e4"},
+		{tile: "1a", square: "a1"},
+		{tile: "8h", square: "h8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.square, func(t *testing.T) {
+			if got := SquareName(tt.tile); got != tt.square {
+				t.Errorf("SquareName(%v) = %v, want %v", tt.tile, got, tt.square)
+			}
+			if got := TileName(tt.square); got != tt.tile {
+				t.Errorf("TileName(%v) = %v, want %v", tt.square, got, tt.tile)
+			}
+		})
+	}
+}
+
+func TestFEN(t *testing.T) {
+	match := getMockNotationMatch()
+
+	if got := match.FEN(); got != StartFEN {
+		t.Errorf("FEN() = %v, want %v", got, StartFEN)
+	}
+
+	movePiece(&match, "2e", "4e")
+	match.AllMoves = append(match.AllMoves, "4e")
+	match.RecordMove()
+	match.IsWhiteTurn = false
+
+	want := "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"
+	if got := match.FEN(); got != want {
+		t.Errorf("FEN() after e4 = %v, want %v", got, want)
+	}
+}
+
+func TestRecordMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *Match)
+		moves   [][2]string
+		check   bool
+		wantSan string
+		wantUci string
+	}{
+		{
+			name:    "Pawn push",
+			moves:   [][2]string{{"2e", "4e"}},
+			wantSan: "e4",
+			wantUci: "e2e4",
+		},
+		{
+			name:    "Knight move",
+			moves:   [][2]string{{"1g", "3f"}},
+			wantSan: "Nf3",
+			wantUci: "g1f3",
+		},
+		{
+			name:    "Capture with check",
+			moves:   [][2]string{{"1d", "7f"}},
+			check:   true,
+			wantSan: "Qxf7+",
+			wantUci: "d1f7",
+		},
+		{
+			name:    "Pawn capture",
+			moves:   [][2]string{{"2e", "7d"}},
+			wantSan: "exd7",
+			wantUci: "e2d7",
+		},
+		{
+			name:    "Kingside castle",
+			moves:   [][2]string{{"1e", "1g"}, {"1h", "1f"}},
+			wantSan: "O-O",
+			wantUci: "e1g1",
+		},
+		{
+			name:    "Queenside castle",
+			moves:   [][2]string{{"1e", "1c"}, {"1a", "1d"}},
+			wantSan: "O-O-O",
+			wantUci: "e1c1",
+		},
+		{
+			name: "Promotion",
+			setup: func(m *Match) {
+				pawn := m.Board["2a"].Piece
+				queen := MakePieces()["white_queen"]
+				pawn.Image = queen.Image
+				m.Pieces[pawn.Name] = pawn
+				square := m.Board["2a"]
+				square.Piece = pawn
+				m.Board["2a"] = square
+			},
+			moves:   [][2]string{{"2a", "7b"}},
+			wantSan: "axb7=Q",
+			wantUci: "a2b7q",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := getMockNotationMatch()
+			match.RecordMove()
+
+			if tt.setup != nil {
+				tt.setup(&match)
+			}
+			for _, move := range tt.moves {
+				movePiece(&match, move[0], move[1])
+			}
+			match.IsBlackUnderCheck = tt.check
+
+			got, ok := match.RecordMove()
+			if !ok {
+				t.Fatalf("RecordMove() found no move")
+			}
+
+			if got.San != tt.wantSan {
+				t.Errorf("RecordMove() san = %v, want %v", got.San, tt.wantSan)
+			}
+			if got.Uci != tt.wantUci {
+				t.Errorf("RecordMove() uci = %v, want %v", got.Uci, tt.wantUci)
+			}
+			if got.Ply != 1 {
+				t.Errorf("RecordMove() ply = %v, want %v", got.Ply, 1)
+			}
+		})
+	}
+}
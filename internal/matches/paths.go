@@ -0,0 +1,38 @@
+package matches
+
+import "strings"
+
+const localPrefix = "local-"
+
+// GamePath is the page of a game in progress. Every game has its own, so a
+// player can have several of them open in different tabs.
+func GamePath(game string) string {
+	return "/game/" + strings.TrimPrefix(game, "online:")
+}
+
+// GameKey turns the id from a game page back into the match key. Local games
+// are known by their id alone, online games are keyed like in WatchGameKey.
+func GameKey(id string) string {
+	if strings.HasPrefix(id, localPrefix) {
+		return id
+	}
+
+	return WatchGameKey(id)
+}
+
+// LocalGameKey is the key of a new local game with the given random id.
+func LocalGameKey(id string) string {
+	return localPrefix + id
+}
+
+// ReplayPath is the page of one replay of a finished game. Every replay has
+// an id of its own, so the same game can be stepped through in several tabs.
+func ReplayPath(matchId, replayId string) string {
+	return "/matches/" + matchId + "?replay=" + replayId
+}
+
+// ReplayKey is the key a finished game is loaded under while it's replayed
+// on the page at ReplayPath.
+func ReplayKey(matchId, replayId string) string {
+	return "database:matchId-" + matchId + ":" + replayId
+}
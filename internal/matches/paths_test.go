@@ -0,0 +1,40 @@
+package matches
+
+import "testing"
+
+func TestGamePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		game     string
+		wantPath string
+	}{
+		{
+			name:     "Online game",
+			game:     "online:abc123",
+			wantPath: "/game/abc123",
+		},
+		{
+			name:     "Local game",
+			game:     LocalGameKey("abc123"),
+			wantPath: "/game/local-abc123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := GamePath(tt.game)
+			if path != tt.wantPath {
+				t.Errorf("GamePath() = %v, want %v", path, tt.wantPath)
+			}
+
+			id := path[len("/game/"):]
+			if got := GameKey(id); got != tt.game {
+				t.Errorf("GameKey(%v) = %v, want %v", id, got, tt.game)
+			}
+		})
+	}
+
+	if got := GameKey("initial"); got == "initial" {
+		t.Errorf("GameKey() reaches the shared initial board")
+	}
+}
@@ -0,0 +1,231 @@
+package matches
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+// MaxPremoves is how long a chain of premoves can get.
+const MaxPremoves = 5
+
+var (
+	ErrPremoveOnTurn   = errors.New("it's your turn, play the move instead")
+	ErrTooManyPremoves = errors.New("too many premoves queued")
+	ErrBadPremove      = errors.New("that piece can't get there")
+)
+
+// Premove is a move queued by the player who isn't on move, played as soon
+// as the opponent's move arrives.
+type Premove struct {
+	From    string
+	To      string
+	Promote bool
+}
+
+func (p Premove) UCI() string {
+	uci := SquareName(p.From) + SquareName(p.To)
+	if p.Promote {
+		uci += "q"
+	}
+
+	return uci
+}
+
+// Premoving reports whether the player's clicks queue premoves, which they do
+// in online games while the opponent is on move.
+func (m *Match) Premoving(player uuid.UUID) bool {
+	color, ok := m.playerColor(player)
+
+	return m.IsOnline && ok && m.Result == "" && color != m.colorToMove()
+}
+
+// premoveBoard is where the player's pieces stand once their queued premoves
+// are played, as far as the player can know without the opponent's moves.
+func (m *Match) premoveBoard(color string) map[string]components.Piece {
+	tiles := make(map[string]components.Piece, len(m.Pieces))
+	for _, piece := range m.Pieces {
+		tiles[piece.Tile] = piece
+	}
+
+	for _, premove := range m.Online.Premoves[color] {
+		piece := tiles[premove.From]
+		delete(tiles, premove.From)
+		piece.Tile = premove.To
+		piece.Moved = true
+		tiles[premove.To] = piece
+	}
+
+	return tiles
+}
+
+// SelectPremove handles the player's click on a tile while the opponent is on
+// move. The first click picks one of the player's pieces, clicking it again
+// drops it, and a click anywhere else queues the premove there.
+func (m *Match) SelectPremove(player uuid.UUID, tile string) error {
+	color, ok := m.playerColor(player)
+	if !ok {
+		return ErrNotAPlayer
+	}
+	if m.Result != "" {
+		return ErrMatchOver
+	}
+	if color == m.colorToMove() {
+		return ErrPremoveOnTurn
+	}
+
+	tiles := m.premoveBoard(color)
+	clicked := tiles[tile]
+	own := clicked.Name != "" && clicked.IsWhite == (color == "white")
+	from := m.Online.PremoveFrom[color]
+
+	if m.Online.PremoveFrom == nil {
+		m.Online.PremoveFrom = make(map[string]string)
+	}
+
+	switch {
+	case from == tile:
+		delete(m.Online.PremoveFrom, color)
+		return nil
+	case own:
+		m.Online.PremoveFrom[color] = tile
+		return nil
+	case from == "":
+		return ErrNotYourPiece
+	}
+
+	delete(m.Online.PremoveFrom, color)
+
+	piece := tiles[from]
+	if !canReach(piece, from, tile) {
+		return ErrBadPremove
+	}
+	if len(m.Online.Premoves[color]) >= MaxPremoves {
+		return ErrTooManyPremoves
+	}
+
+	if m.Online.Premoves == nil {
+		m.Online.Premoves = make(map[string][]Premove)
+	}
+	m.Online.Premoves[color] = append(m.Online.Premoves[color], Premove{
+		From:    from,
+		To:      tile,
+		Promote: piece.IsPawn && (tile[:1] == "8" || tile[:1] == "1"),
+	})
+
+	return nil
+}
+
+// PremoveTiles are the tiles to highlight for the player: both ends of every
+// queued premove and the piece picked for the next one.
+func (m *Match) PremoveTiles(player uuid.UUID) []string {
+	color, ok := m.playerColor(player)
+	if !ok {
+		return nil
+	}
+
+	var tiles []string
+	for _, premove := range m.Online.Premoves[color] {
+		tiles = append(tiles, premove.From, premove.To)
+	}
+	if from := m.Online.PremoveFrom[color]; from != "" {
+		tiles = append(tiles, from)
+	}
+
+	return tiles
+}
+
+// CancelPremoves drops every premove the player queued.
+func (m *Match) CancelPremoves(player uuid.UUID) {
+	if color, ok := m.playerColor(player); ok {
+		delete(m.Online.Premoves, color)
+		delete(m.Online.PremoveFrom, color)
+	}
+}
+
+// NextPremove takes the first premove of the player on move off their chain.
+// It returns that player whether they have one or not.
+func (m *Match) NextPremove() (uuid.UUID, Premove, bool) {
+	color := m.colorToMove()
+	player := m.Online.Players[color].ID
+	chain := m.Online.Premoves[color]
+	if !m.IsOnline || m.Result != "" || len(chain) == 0 {
+		return player, Premove{}, false
+	}
+
+	m.Online.Premoves[color] = chain[1:]
+
+	return player, chain[0], true
+}
+
+// canReach reports whether the piece could get from one tile to the other on
+// an empty board. Premoves can't know what the opponent will move in the way,
+// so that's all a premove is checked against before it's played.
+func canReach(piece components.Piece, from, to string) bool {
+	fromRow, fromCol := tilePosition(from)
+	toRow, toCol := tilePosition(to)
+	if fromRow < 0 || toRow < 0 || from == to {
+		return false
+	}
+	rows := toRow - fromRow
+	cols := toCol - fromCol
+
+	if piece.IsPawn {
+		forward := 1
+		startRank := "7"
+		if piece.IsWhite {
+			forward = -1
+			startRank = "2"
+		}
+
+		return rows == forward && cols >= -1 && cols <= 1 ||
+			rows == 2*forward && cols == 0 && from[:1] == startRank
+	}
+
+	if piece.IsKing && !piece.Moved && rows == 0 && (cols == 2 || cols == -2) {
+		return true
+	}
+
+	for _, move := range piece.LegalMoves {
+		for step := 1; step < len(MockBoard); step++ {
+			if rows == move[0]*step && cols == move[1]*step {
+				return true
+			}
+			if piece.MovesOnce {
+				break
+			}
+		}
+	}
+
+	return false
+}
+
+func tilePosition(tile string) (int, int) {
+	if len(tile) != 2 {
+		return -1, -1
+	}
+
+	row, ok := RowIdxMap[tile[:1]]
+	col := strings.Index("abcdefgh", tile[1:])
+	if !ok || col < 0 {
+		return -1, -1
+	}
+
+	return row, col
+}
+
+func clonePremoves(premoves map[string][]Premove) map[string][]Premove {
+	if premoves == nil {
+		return nil
+	}
+
+	clone := make(map[string][]Premove, len(premoves))
+	for color, chain := range premoves {
+		clone[color] = slices.Clone(chain)
+	}
+
+	return clone
+}
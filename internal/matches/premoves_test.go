@@ -0,0 +1,173 @@
+package matches
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestSelectPremove(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	tests := []struct {
+		name      string
+		player    uuid.UUID
+		clicks    []string
+		wantErr   error
+		wantChain []Premove
+		wantFrom  string
+	}{
+		{
+			name:     "Pick a piece",
+			player:   black,
+			clicks:   []string{"7e"},
+			wantFrom: "7e",
+		},
+		{
+			name:   "Drop the picked piece",
+			player: black,
+			clicks: []string{"7e", "7e"},
+		},
+		{
+			name:      "Queue a premove",
+			player:    black,
+			clicks:    []string{"7e", "5e"},
+			wantChain: []Premove{{From: "7e", To: "5e"}},
+		},
+		{
+			name:      "Chain premoves",
+			player:    black,
+			clicks:    []string{"7e", "5e", "5e", "4e"},
+			wantChain: []Premove{{From: "7e", To: "5e"}, {From: "5e", To: "4e"}},
+		},
+		{
+			name:      "Knight premove",
+			player:    black,
+			clicks:    []string{"8g", "6f"},
+			wantChain: []Premove{{From: "8g", To: "6f"}},
+		},
+		{
+			name:    "Piece can't get there",
+			player:  black,
+			clicks:  []string{"8g", "5g"},
+			wantErr: ErrBadPremove,
+		},
+		{
+			name:    "Opponent's piece",
+			player:  black,
+			clicks:  []string{"2e"},
+			wantErr: ErrNotYourPiece,
+		},
+		{
+			name:    "On move",
+			player:  white,
+			clicks:  []string{"2e"},
+			wantErr: ErrPremoveOnTurn,
+		},
+		{
+			name:    "Not a player",
+			player:  uuid.New(),
+			clicks:  []string{"7e"},
+			wantErr: ErrNotAPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := getMockNotationMatch()
+			match.IsOnline = true
+			match.Online.Players = map[string]components.OnlinePlayerStruct{
+				"white": {ID: white},
+				"black": {ID: black},
+			}
+
+			var err error
+			for _, tile := range tt.clicks {
+				err = match.SelectPremove(tt.player, tile)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SelectPremove() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if chain := match.Online.Premoves["black"]; !slices.Equal(chain, tt.wantChain) {
+				t.Errorf("SelectPremove() chain = %v, want %v", chain, tt.wantChain)
+			}
+			if from := match.Online.PremoveFrom["black"]; from != tt.wantFrom {
+				t.Errorf("SelectPremove() picked %q, want %q", from, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestNextPremove(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	match := getMockNotationMatch()
+	match.IsOnline = true
+	match.Online.Players = map[string]components.OnlinePlayerStruct{
+		"white": {ID: white},
+		"black": {ID: black},
+	}
+
+	for _, tile := range []string{"7e", "5e", "8g", "6f"} {
+		if err := match.SelectPremove(black, tile); err != nil {
+			t.Fatalf("SelectPremove(%v) error = %v", tile, err)
+		}
+	}
+
+	if _, _, ok := match.NextPremove(); ok {
+		t.Fatalf("NextPremove() played black's premove on white's turn")
+	}
+
+	playMove(&match, "2e", "4e")
+
+	player, premove, ok := match.NextPremove()
+	if !ok || player != black || premove.UCI() != "e7e5" {
+		t.Fatalf("NextPremove() = %v, %v, %v, want %v, e7e5, true", player, premove.UCI(), ok, black)
+	}
+	if got := match.PremoveTiles(black); !slices.Equal(got, []string{"8g", "6f"}) {
+		t.Errorf("PremoveTiles() = %v, want the rest of the chain", got)
+	}
+
+	match.CancelPremoves(black)
+	if got := match.PremoveTiles(black); len(got) != 0 {
+		t.Errorf("PremoveTiles() = %v after CancelPremoves()", got)
+	}
+}
+
+func TestCanReach(t *testing.T) {
+	pieces := MakePieces()
+
+	tests := []struct {
+		name  string
+		piece string
+		from  string
+		to    string
+		want  bool
+	}{
+		{"Pawn one step", "white_pawn_5", "2e", "3e", true},
+		{"Pawn two steps from the start", "white_pawn_5", "2e", "4e", true},
+		{"Pawn two steps later on", "white_pawn_5", "3e", "5e", false},
+		{"Pawn capture", "black_pawn_4", "7d", "6e", true},
+		{"Pawn backwards", "black_pawn_4", "6d", "7d", false},
+		{"Knight jump", "right_white_knight", "1b", "3c", true},
+		{"Knight straight", "right_white_knight", "1b", "3b", false},
+		{"Bishop across the board", "right_white_bishop", "1c", "6h", true},
+		{"Queen like a knight", "white_queen", "1d", "3e", false},
+		{"King castles", "white_king", "1e", "1g", true},
+		{"King two steps up", "white_king", "1e", "3e", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canReach(pieces[tt.piece], tt.from, tt.to); got != tt.want {
+				t.Errorf("canReach(%v, %v, %v) = %v, want %v", tt.piece, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package matches
+
+import (
+	"errors"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+const ReconnectGracePeriod = 30 * time.Second
+
+var ErrNotAPlayer = errors.New("not a player in this game")
+
+func (m *Match) MarkDisconnected(playerId uuid.UUID, now time.Time) time.Time {
+	if m.Online.Disconnected == nil {
+		m.Online.Disconnected = make(map[uuid.UUID]time.Time)
+	}
+
+	deadline := now.Add(ReconnectGracePeriod)
+	m.Online.Disconnected[playerId] = deadline
+
+	return deadline
+}
+
+func (m *Match) MarkReconnected(playerId uuid.UUID) bool {
+	_, ok := m.Online.Disconnected[playerId]
+	delete(m.Online.Disconnected, playerId)
+
+	return ok
+}
+
+func (m *Match) ReconnectSecondsLeft(playerId uuid.UUID, now time.Time) (int, bool) {
+	deadline, ok := m.Online.Disconnected[playerId]
+	if !ok {
+		return 0, false
+	}
+
+	left := int(deadline.Sub(now).Round(time.Second) / time.Second)
+
+	return max(left, 0), true
+}
+
+func (m *Match) ReconnectGraceExpired(playerId uuid.UUID, now time.Time) bool {
+	deadline, ok := m.Online.Disconnected[playerId]
+
+	return ok && !now.Before(deadline)
+}
+
+func (m *Match) IsPlayer(playerId uuid.UUID) bool {
+	return playerId != uuid.Nil && (m.Online.Players["white"].ID == playerId || m.Online.Players["black"].ID == playerId)
+}
+
+// Opponent is the other player seated in the game, for a player of it only.
+func (m *Match) Opponent(playerId uuid.UUID) (components.OnlinePlayerStruct, bool) {
+	if !m.IsPlayer(playerId) {
+		return components.OnlinePlayerStruct{}, false
+	}
+
+	for _, player := range m.Online.Players {
+		if player.ID != playerId && player.ID != uuid.Nil {
+			return player, true
+		}
+	}
+
+	return components.OnlinePlayerStruct{}, false
+}
+
+// AbandonmentResult returns the result of a game the player walked away from.
+// Until both players moved the game is aborted instead of lost.
+func (m *Match) AbandonmentResult(playerId uuid.UUID) (result, winner string, aborted bool, err error) {
+	if !m.IsPlayer(playerId) {
+		return "", "", false, ErrNotAPlayer
+	}
+
+	if !m.bothMoved() {
+		return "*", "", true, nil
+	}
+
+	if m.Online.Players["white"].ID == playerId {
+		return "0-1", "black", false, nil
+	}
+
+	return "1-0", "white", false, nil
+}
@@ -0,0 +1,229 @@
+package matches
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestReconnectGrace(t *testing.T) {
+	playerId := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		disconnect  bool
+		reconnect   bool
+		elapsed     time.Duration
+		wantWaiting bool
+		wantSeconds int
+		wantExpired bool
+	}{
+		{
+			name:        "Connected player",
+			wantWaiting: false,
+		},
+		{
+			name:        "Just disconnected",
+			disconnect:  true,
+			wantWaiting: true,
+			wantSeconds: 30,
+		},
+		{
+			name:        "Halfway through the grace period",
+			disconnect:  true,
+			elapsed:     12 * time.Second,
+			wantWaiting: true,
+			wantSeconds: 18,
+		},
+		{
+			name:        "Grace period ran out",
+			disconnect:  true,
+			elapsed:     ReconnectGracePeriod,
+			wantWaiting: true,
+			wantSeconds: 0,
+			wantExpired: true,
+		},
+		{
+			name:        "Reconnected in time",
+			disconnect:  true,
+			reconnect:   true,
+			elapsed:     ReconnectGracePeriod,
+			wantWaiting: false,
+			wantExpired: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{}
+
+			if tt.disconnect {
+				match.MarkDisconnected(playerId, now)
+			}
+			if tt.reconnect && !match.MarkReconnected(playerId) {
+				t.Errorf("MarkReconnected() = false, want true")
+			}
+
+			seconds, waiting := match.ReconnectSecondsLeft(playerId, now.Add(tt.elapsed))
+			if waiting != tt.wantWaiting {
+				t.Errorf("ReconnectSecondsLeft() waiting = %v, want %v", waiting, tt.wantWaiting)
+			}
+			if seconds != tt.wantSeconds {
+				t.Errorf("ReconnectSecondsLeft() seconds = %v, want %v", seconds, tt.wantSeconds)
+			}
+
+			expired := match.ReconnectGraceExpired(playerId, now.Add(tt.elapsed))
+			if expired != tt.wantExpired {
+				t.Errorf("ReconnectGraceExpired() = %v, want %v", expired, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestAbandonmentResult(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	tests := []struct {
+		name        string
+		moves       []string
+		leaver      uuid.UUID
+		wantResult  string
+		wantWinner  string
+		wantAborted bool
+		wantErr     error
+	}{
+		{
+			name:        "No moves made",
+			moves:       []string{},
+			leaver:      white,
+			wantResult:  "*",
+			wantAborted: true,
+		},
+		{
+			name:       "White left",
+			moves:      []string{"e4", "e5"},
+			leaver:     white,
+			wantResult: "0-1",
+			wantWinner: "black",
+		},
+		{
+			name:        "Black left before moving",
+			moves:       []string{"e4"},
+			leaver:      black,
+			wantResult:  "*",
+			wantAborted: true,
+		},
+		{
+			name:       "Black left",
+			moves:      []string{"e4", "e5", "Nf3"},
+			leaver:     black,
+			wantResult: "1-0",
+			wantWinner: "white",
+		},
+		{
+			name:    "Someone who isn't playing",
+			moves:   []string{"e4", "e5"},
+			leaver:  uuid.New(),
+			wantErr: ErrNotAPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := Match{
+				AllMoves: tt.moves,
+				Online: OnlineGame{
+					Players: map[string]components.OnlinePlayerStruct{
+						"white": {ID: white},
+						"black": {ID: black},
+					},
+				},
+			}
+
+			result, winner, aborted, err := match.AbandonmentResult(tt.leaver)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AbandonmentResult() error = %v, want %v", err, tt.wantErr)
+			}
+			if result != tt.wantResult || winner != tt.wantWinner || aborted != tt.wantAborted {
+				t.Errorf("AbandonmentResult() = %v, %v, %v, want %v, %v, %v", result, winner, aborted, tt.wantResult, tt.wantWinner, tt.wantAborted)
+			}
+		})
+	}
+}
+
+func TestIsPlayer(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	match := Match{
+		Online: OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: white},
+				"black": {},
+			},
+		},
+	}
+
+	if !match.IsPlayer(white) {
+		t.Errorf("IsPlayer(white) = false, want true")
+	}
+	if match.IsPlayer(black) {
+		t.Errorf("IsPlayer() before black is seated = true, want false")
+	}
+	if match.IsPlayer(uuid.Nil) {
+		t.Errorf("IsPlayer(uuid.Nil) = true, want false")
+	}
+}
+
+func TestOpponent(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	match := Match{
+		Online: OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: white},
+				"black": {ID: black},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		player uuid.UUID
+		want   uuid.UUID
+		wantOk bool
+	}{
+		{
+			name:   "White's opponent",
+			player: white,
+			want:   black,
+			wantOk: true,
+		},
+		{
+			name:   "Black's opponent",
+			player: black,
+			want:   white,
+			wantOk: true,
+		},
+		{
+			name:   "Someone who isn't playing",
+			player: uuid.New(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opponent, ok := match.Opponent(tt.player)
+			if ok != tt.wantOk || opponent.ID != tt.want {
+				t.Errorf("Opponent() = %v, %v, want %v, %v", opponent.ID, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package matches
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+)
+
+// Matches is the registry of every live match. Each match is owned by its own
+// goroutine, so all reads and writes of a Match go through Do and are applied
+// one at a time, in the order they arrive.
+type Matches struct {
+	mu      sync.RWMutex
+	matches map[string]*matchActor
+	onEnd   func(string, Match)
+	onMove  func(string, Match)
+}
+
+type matchActor struct {
+	match    *Match
+	isOnline bool
+	onEnd    func(Match)
+	onMove   func(Match)
+	commands chan command
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+type command struct {
+	fn   func(*Match)
+	done chan struct{}
+}
+
+func NewMatches() *Matches {
+	return &Matches{
+		matches: make(map[string]*matchActor),
+	}
+}
+
+// OnEnd registers fn to be called with the key and a copy of every match set
+// after it, the moment the match gets its result. It runs on its own
+// goroutine, so it may use the registry.
+func (m *Matches) OnEnd(fn func(string, Match)) {
+	m.mu.Lock()
+	m.onEnd = fn
+	m.mu.Unlock()
+}
+
+// OnMove registers fn to be called with the key and a copy of the match every
+// time a move is played in it. Like OnEnd it runs on its own goroutine.
+func (m *Matches) OnMove(fn func(string, Match)) {
+	m.mu.Lock()
+	m.onMove = fn
+	m.mu.Unlock()
+}
+
+func newMatchActor(match Match, onEnd, onMove func(Match)) *matchActor {
+	a := &matchActor{
+		match:    &match,
+		isOnline: match.IsOnline,
+		onEnd:    onEnd,
+		onMove:   onMove,
+		commands: make(chan command),
+		quit:     make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *matchActor) run() {
+	for {
+		select {
+		case cmd := <-a.commands:
+			a.execute(cmd)
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+func (a *matchActor) execute(cmd command) {
+	defer close(cmd.done)
+	defer func() {
+		if r := recover(); r != nil {
+			responses.LogError("match command panicked", fmt.Errorf("%v", r))
+		}
+	}()
+
+	ended := a.match.Result != ""
+	played := len(a.match.UciMoves)
+
+	cmd.fn(a.match)
+
+	if !ended && a.match.Result != "" && a.onEnd != nil {
+		go a.onEnd(a.match.Clone())
+	}
+	if len(a.match.UciMoves) > played && a.onMove != nil {
+		go a.onMove(a.match.Clone())
+	}
+}
+
+func (a *matchActor) stop() {
+	a.stopOnce.Do(func() {
+		close(a.quit)
+	})
+}
+
+func (m *Matches) getActor(key string) (*matchActor, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, ok := m.matches[key]
+	return a, ok
+}
+
+// Do runs fn on the goroutine that owns the match and waits for it to finish.
+// fn must not call Do for the same key, or it will wait on itself forever.
+func (m *Matches) Do(key string, fn func(*Match)) bool {
+	a, ok := m.getActor(key)
+	if !ok {
+		return false
+	}
+
+	cmd := command{
+		fn:   fn,
+		done: make(chan struct{}),
+	}
+
+	select {
+	case a.commands <- cmd:
+	case <-a.quit:
+		return false
+	}
+
+	<-cmd.done
+
+	return true
+}
+
+func (m *Matches) GetMatch(key string) (Match, bool) {
+	var snapshot Match
+	found := m.Do(key, func(match *Match) {
+		snapshot = match.Clone()
+	})
+
+	return snapshot, found
+}
+
+func (m *Matches) SetMatch(key string, match Match) {
+	m.mu.RLock()
+	onEnd := m.onEnd
+	onMove := m.onMove
+	m.mu.RUnlock()
+
+	var actorOnEnd func(Match)
+	if onEnd != nil {
+		actorOnEnd = func(finished Match) {
+			onEnd(key, finished)
+		}
+	}
+
+	var actorOnMove func(Match)
+	if onMove != nil {
+		actorOnMove = func(moved Match) {
+			onMove(key, moved)
+		}
+	}
+
+	a := newMatchActor(match, actorOnEnd, actorOnMove)
+
+	m.mu.Lock()
+	old, ok := m.matches[key]
+	m.matches[key] = a
+	m.mu.Unlock()
+
+	if ok {
+		old.stop()
+	}
+}
+
+func (m *Matches) DeleteMatch(key string) {
+	m.mu.Lock()
+	a, ok := m.matches[key]
+	delete(m.matches, key)
+	m.mu.Unlock()
+
+	if ok {
+		a.stop()
+	}
+}
+
+func (m *Matches) HasMatch(key string) bool {
+	_, ok := m.getActor(key)
+	return ok
+}
+
+func (m *Matches) GetInitialMatch() Match {
+	match, _ := m.GetMatch("initial")
+	return match
+}
+
+func (m *Matches) GetAllOnlineMatches() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var onlineMatches []string
+	for name, a := range m.matches {
+		if a.isOnline {
+			onlineMatches = append(onlineMatches, name)
+		}
+	}
+
+	slices.Sort(onlineMatches)
+
+	return onlineMatches
+}
+
+// GetAllMatches lists the keys of every match, local and online.
+func (m *Matches) GetAllMatches() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := slices.Collect(maps.Keys(m.matches))
+	slices.Sort(keys)
+
+	return keys
+}
+
+func (m *Match) Clone() Match {
+	clone := *m
+
+	clone.Board = maps.Clone(m.Board)
+	clone.Pieces = maps.Clone(m.Pieces)
+	clone.TilesUnderAttack = slices.Clone(m.TilesUnderAttack)
+	clone.AllMoves = slices.Clone(m.AllMoves)
+	clone.PiecesSnapshot = slices.Clone(m.PiecesSnapshot)
+	clone.TakenPiecesWhite = slices.Clone(m.TakenPiecesWhite)
+	clone.TakenPiecesBlack = slices.Clone(m.TakenPiecesBlack)
+	clone.LastPosition = maps.Clone(m.LastPosition)
+	clone.UciMoves = slices.Clone(m.UciMoves)
+	clone.SanMoves = slices.Clone(m.SanMoves)
+	clone.History = slices.Clone(m.History)
+	clone.LastDrawOffers = maps.Clone(m.LastDrawOffers)
+
+	if m.Online.Players != nil {
+		clone.Online.Players = make(map[string]components.OnlinePlayerStruct, len(m.Online.Players))
+		maps.Copy(clone.Online.Players, m.Online.Players)
+	}
+	clone.Online.Disconnected = maps.Clone(m.Online.Disconnected)
+	clone.Online.SpectatorMultipliers = maps.Clone(m.Online.SpectatorMultipliers)
+	clone.Online.Premoves = clonePremoves(m.Online.Premoves)
+	clone.Online.PremoveFrom = maps.Clone(m.Online.PremoveFrom)
+	clone.Online.LagCredit = maps.Clone(m.Online.LagCredit)
+
+	return clone
+}
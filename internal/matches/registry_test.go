@@ -0,0 +1,260 @@
+package matches
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func getMockRegistryMatch(timer int) Match {
+	match := Match{
+		Board:          MakeBoard(),
+		Pieces:         MakePieces(),
+		IsWhiteTurn:    true,
+		WhiteTimer:     timer,
+		BlackTimer:     timer,
+		TurnStartTimer: timer,
+	}
+	match.FillBoard()
+
+	return match
+}
+
+func TestDoSerializesMovesAndTimerTicks(t *testing.T) {
+	const (
+		timer         = 100000
+		movers        = 20
+		movesPerMover = 25
+		tickers       = 20
+		ticksPerTimer = 50
+	)
+
+	registry := NewMatches()
+	registry.SetMatch("game", getMockRegistryMatch(timer))
+
+	var wg sync.WaitGroup
+
+	for i := range movers {
+		wg.Add(1)
+		go func(mover int) {
+			defer wg.Done()
+			for j := range movesPerMover {
+				registry.Do("game", func(m *Match) {
+					m.AllMoves = append(m.AllMoves, fmt.Sprintf("%v-%v", mover, j))
+					m.EndTurn(httptest.NewRecorder())
+				})
+			}
+		}(i)
+	}
+
+	for range tickers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range ticksPerTimer {
+				registry.Do("game", func(m *Match) {
+					m.TickTimer()
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	match, ok := registry.GetMatch("game")
+	if !ok {
+		t.Fatalf("GetMatch() found = %v, want %v", ok, true)
+	}
+
+	if len(match.AllMoves) != movers*movesPerMover {
+		t.Errorf("AllMoves length = %v, want %v", len(match.AllMoves), movers*movesPerMover)
+	}
+
+	ticked := 2*timer - match.WhiteTimer - match.BlackTimer
+	if ticked != tickers*ticksPerTimer {
+		t.Errorf("timer ticks = %v, want %v", ticked, tickers*ticksPerTimer)
+	}
+
+	wantWhiteTurn := (movers*movesPerMover)%2 == 0
+	if match.IsWhiteTurn != wantWhiteTurn {
+		t.Errorf("IsWhiteTurn = %v, want %v", match.IsWhiteTurn, wantWhiteTurn)
+	}
+}
+
+func TestConcurrentRegistryAccess(t *testing.T) {
+	registry := NewMatches()
+
+	var wg sync.WaitGroup
+
+	for i := range 20 {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			key := fmt.Sprintf("online:%v", n%5)
+			for range 20 {
+				match := getMockRegistryMatch(600)
+				match.IsOnline = n%2 == 0
+				registry.SetMatch(key, match)
+				registry.Do(key, func(m *Match) {
+					m.TickTimer()
+				})
+				_, _ = registry.GetMatch(key)
+				_ = registry.GetAllOnlineMatches()
+				registry.DeleteMatch(key)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if len(registry.GetAllOnlineMatches()) != 0 {
+		t.Errorf("GetAllOnlineMatches() = %v, want none", registry.GetAllOnlineMatches())
+	}
+}
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(*Matches)
+		key       string
+		fn        func(*Match)
+		wantFound bool
+	}{
+		{
+			name:      "Match doesn't exist",
+			setup:     func(m *Matches) {},
+			key:       "missing",
+			fn:        func(m *Match) {},
+			wantFound: false,
+		},
+		{
+			name: "Match was deleted",
+			setup: func(m *Matches) {
+				m.SetMatch("deleted", getMockRegistryMatch(600))
+				m.DeleteMatch("deleted")
+			},
+			key:       "deleted",
+			fn:        func(m *Match) {},
+			wantFound: false,
+		},
+		{
+			name: "Command panics",
+			setup: func(m *Matches) {
+				m.SetMatch("game", getMockRegistryMatch(600))
+			},
+			key: "game",
+			fn: func(m *Match) {
+				panic("bad command")
+			},
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewMatches()
+			tt.setup(registry)
+
+			found := registry.Do(tt.key, tt.fn)
+
+			if found != tt.wantFound {
+				t.Errorf("Do() found = %v, want %v", found, tt.wantFound)
+			}
+
+			if found && !registry.Do(tt.key, func(m *Match) {}) {
+				t.Errorf("Do() match stopped answering after command")
+			}
+		})
+	}
+}
+
+func TestGetMatchReturnsCopy(t *testing.T) {
+	registry := NewMatches()
+	registry.SetMatch("game", getMockRegistryMatch(600))
+
+	snapshot, _ := registry.GetMatch("game")
+	delete(snapshot.Pieces, "white_king")
+	snapshot.AllMoves = append(snapshot.AllMoves, "4e")
+
+	match, _ := registry.GetMatch("game")
+
+	if _, ok := match.Pieces["white_king"]; !ok {
+		t.Errorf("GetMatch() snapshot shares pieces with the registry")
+	}
+
+	if len(match.AllMoves) != 0 {
+		t.Errorf("GetMatch() snapshot shares moves with the registry")
+	}
+}
+
+func TestOnEnd(t *testing.T) {
+	type ending struct {
+		key   string
+		match Match
+	}
+
+	registry := NewMatches()
+	ended := make(chan ending, 4)
+	registry.OnEnd(func(key string, match Match) {
+		ended <- ending{key, match}
+	})
+	registry.SetMatch("game", getMockRegistryMatch(600))
+
+	registry.Do("game", func(m *Match) {
+		m.TickTimer()
+	})
+	registry.Do("game", func(m *Match) {
+		m.PublishGameEnd("0-1", TerminationResignation)
+	})
+	registry.Do("game", func(m *Match) {
+		m.PublishGameEnd("1-0", TerminationTimeout)
+	})
+
+	select {
+	case got := <-ended:
+		if got.key != "game" || got.match.Result != "0-1" || got.match.Termination != TerminationResignation {
+			t.Errorf("OnEnd() = %v, %v %v, want game, 0-1 resignation", got.key, got.match.Result, got.match.Termination)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("OnEnd() was never called")
+	}
+
+	select {
+	case got := <-ended:
+		t.Errorf("OnEnd() called again with %v", got.match.Result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestOnMove(t *testing.T) {
+	registry := NewMatches()
+	moved := make(chan Match, 4)
+	registry.OnMove(func(key string, match Match) {
+		moved <- match
+	})
+	registry.SetMatch("game", getMockRegistryMatch(600))
+
+	registry.Do("game", func(m *Match) {
+		m.TickTimer()
+	})
+	registry.Do("game", func(m *Match) {
+		m.UciMoves = append(m.UciMoves, "e2e4")
+	})
+
+	select {
+	case got := <-moved:
+		if len(got.UciMoves) != 1 {
+			t.Errorf("OnMove() moves = %v, want [e2e4]", got.UciMoves)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("OnMove() was never called")
+	}
+
+	select {
+	case got := <-moved:
+		t.Errorf("OnMove() called again with %v", got.UciMoves)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,160 @@
+package matches
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+// RematchTimeout is how long a finished game stays open for a rematch, and
+// how long the opponent has to accept one once it's offered.
+const RematchTimeout = 30 * time.Second
+
+var (
+	ErrNoRematch        = errors.New("rematch is no longer available")
+	ErrNotRematchPlayer = errors.New("not a player in this game")
+)
+
+// Rematch is the offer to play a finished online game again with the same
+// time control and the colors swapped.
+type Rematch struct {
+	White     components.OnlinePlayerStruct
+	Black     components.OnlinePlayerStruct
+	FullTime  int
+	Addition  int
+	Rated     bool
+	MatchId   int32
+	OfferedBy uuid.UUID
+	Declined  bool
+	Accepted  bool
+	NewGame   string
+	expires   time.Time
+}
+
+func (r Rematch) IsPlayer(id uuid.UUID) bool {
+	return id != uuid.Nil && (r.White.ID == id || r.Black.ID == id)
+}
+
+// Rematches holds the rematch offers of finished online games, keyed by the
+// game they would replay.
+type Rematches struct {
+	mu     sync.Mutex
+	offers map[string]*Rematch
+}
+
+func NewRematches() *Rematches {
+	return &Rematches{offers: make(map[string]*Rematch)}
+}
+
+// Open makes a finished match available for a rematch. Both players end the
+// game, so opening the same game twice keeps the first offer.
+func (r *Rematches) Open(game string, match Match, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, rematch := range r.offers {
+		if now.After(rematch.expires) {
+			delete(r.offers, key)
+		}
+	}
+
+	if _, ok := r.offers[game]; ok {
+		return
+	}
+
+	r.offers[game] = &Rematch{
+		White:    match.Online.Players["white"],
+		Black:    match.Online.Players["black"],
+		FullTime: match.FullTime,
+		Addition: match.Addition,
+		Rated:    match.Rated,
+		MatchId:  match.MatchId,
+		expires:  now.Add(RematchTimeout),
+	}
+}
+
+func (r *Rematches) Get(game string, now time.Time) (Rematch, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rematch, ok := r.get(game, now)
+	if !ok {
+		return Rematch{}, false
+	}
+
+	return *rematch, true
+}
+
+// Offer offers a rematch on behalf of the player, or accepts it if their
+// opponent already offered one. Only one call can accept, and it's up to the
+// caller to start the new game and report it with Started.
+func (r *Rematches) Offer(game string, userId uuid.UUID, now time.Time) (Rematch, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rematch, ok := r.get(game, now)
+	if !ok || rematch.Declined || rematch.Accepted {
+		return Rematch{}, false, ErrNoRematch
+	}
+
+	if !rematch.IsPlayer(userId) {
+		return Rematch{}, false, ErrNotRematchPlayer
+	}
+
+	if rematch.OfferedBy != uuid.Nil && rematch.OfferedBy != userId {
+		rematch.Accepted = true
+		return *rematch, true, nil
+	}
+
+	rematch.OfferedBy = userId
+	rematch.expires = now.Add(RematchTimeout)
+
+	return *rematch, false, nil
+}
+
+// Started records the game the rematch is played in, so the player who
+// offered it can follow.
+func (r *Rematches) Started(game, newGame string, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rematch, ok := r.offers[game]; ok {
+		rematch.NewGame = newGame
+		rematch.expires = now.Add(RematchTimeout)
+	}
+}
+
+func (r *Rematches) Decline(game string, userId uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rematch, ok := r.get(game, now)
+	if !ok || rematch.Accepted {
+		return ErrNoRematch
+	}
+
+	if !rematch.IsPlayer(userId) {
+		return ErrNotRematchPlayer
+	}
+
+	rematch.Declined = true
+
+	return nil
+}
+
+func (r *Rematches) get(game string, now time.Time) (*Rematch, bool) {
+	rematch, ok := r.offers[game]
+	if !ok {
+		return nil, false
+	}
+
+	if now.After(rematch.expires) {
+		delete(r.offers, game)
+		return nil, false
+	}
+
+	return rematch, true
+}
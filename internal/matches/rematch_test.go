@@ -0,0 +1,147 @@
+package matches
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func getMockFinishedMatch(white, black uuid.UUID) Match {
+	return Match{
+		IsOnline: true,
+		FullTime: 600,
+		Addition: 3,
+		Online: OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: white, Pieces: "white"},
+				"black": {ID: black, Pieces: "black"},
+			},
+		},
+	}
+}
+
+func TestRematchOffer(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		act          func(r *Rematches)
+		offerBy      uuid.UUID
+		offerAt      time.Time
+		wantAccepted bool
+		wantErr      error
+	}{
+		{
+			name:    "First offer waits for the opponent",
+			act:     func(r *Rematches) {},
+			offerBy: white,
+			offerAt: now,
+		},
+		{
+			name: "Opponent's offer accepts",
+			act: func(r *Rematches) {
+				_, _, _ = r.Offer("online:game", white, now)
+			},
+			offerBy:      black,
+			offerAt:      now.Add(time.Second),
+			wantAccepted: true,
+		},
+		{
+			name: "Offering twice doesn't accept",
+			act: func(r *Rematches) {
+				_, _, _ = r.Offer("online:game", white, now)
+			},
+			offerBy: white,
+			offerAt: now.Add(time.Second),
+		},
+		{
+			name: "Accepting too late",
+			act: func(r *Rematches) {
+				_, _, _ = r.Offer("online:game", white, now)
+			},
+			offerBy: black,
+			offerAt: now.Add(RematchTimeout + time.Second),
+			wantErr: ErrNoRematch,
+		},
+		{
+			name: "Declined rematch",
+			act: func(r *Rematches) {
+				_, _, _ = r.Offer("online:game", white, now)
+				_ = r.Decline("online:game", black, now)
+			},
+			offerBy: black,
+			offerAt: now.Add(time.Second),
+			wantErr: ErrNoRematch,
+		},
+		{
+			name: "Only one accept",
+			act: func(r *Rematches) {
+				_, _, _ = r.Offer("online:game", white, now)
+				_, _, _ = r.Offer("online:game", black, now)
+			},
+			offerBy: black,
+			offerAt: now.Add(time.Second),
+			wantErr: ErrNoRematch,
+		},
+		{
+			name:    "Someone else",
+			act:     func(r *Rematches) {},
+			offerBy: uuid.New(),
+			offerAt: now,
+			wantErr: ErrNotRematchPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rematches := NewRematches()
+			rematches.Open("online:game", getMockFinishedMatch(white, black), now)
+			tt.act(rematches)
+
+			rematch, accepted, err := rematches.Offer("online:game", tt.offerBy, tt.offerAt)
+			if err != tt.wantErr {
+				t.Fatalf("Offer() error = %v, want %v", err, tt.wantErr)
+			}
+			if accepted != tt.wantAccepted {
+				t.Errorf("Offer() accepted = %v, want %v", accepted, tt.wantAccepted)
+			}
+			if err == nil && (rematch.FullTime != 600 || rematch.Addition != 3) {
+				t.Errorf("Offer() time control = %v+%v, want 600+3", rematch.FullTime, rematch.Addition)
+			}
+		})
+	}
+}
+
+func TestRematchOpen(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+	now := time.Now()
+
+	rematches := NewRematches()
+	rematches.Open("online:game", getMockFinishedMatch(white, black), now)
+	_, _, _ = rematches.Offer("online:game", white, now)
+	rematches.Open("online:game", Match{}, now)
+
+	rematch, ok := rematches.Get("online:game", now)
+	if !ok {
+		t.Fatalf("Get() found no rematch")
+	}
+	if rematch.OfferedBy != white {
+		t.Errorf("opening again reset the offer, OfferedBy = %v, want %v", rematch.OfferedBy, white)
+	}
+
+	rematches.Open("online:other", getMockFinishedMatch(white, black), now.Add(RematchTimeout*2))
+	if _, ok := rematches.Get("online:game", now); ok {
+		t.Errorf("expired rematch wasn't cleaned up")
+	}
+
+	rematches.Started("online:other", "online:new", now.Add(RematchTimeout*2))
+	rematch, _ = rematches.Get("online:other", now.Add(RematchTimeout*2))
+	if rematch.NewGame != "online:new" {
+		t.Errorf("NewGame = %v, want online:new", rematch.NewGame)
+	}
+}
@@ -2,7 +2,7 @@ package matches
 
 import (
 	"fmt"
-	"net/http"
+	"io"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
@@ -10,7 +10,7 @@ import (
 	"github.com/google/uuid"
 )
 
-func (m *Match) RespondWithCheck(w http.ResponseWriter, square components.Square, king components.Piece) error {
+func (m *Match) RespondWithCheck(w io.Writer, square components.Square, king components.Piece) error {
 	className := `class="bg-red-400"`
 	message := fmt.Sprintf(
 		responses.GetSinglePieceMessage(),
@@ -29,7 +29,7 @@ func (m *Match) RespondWithCheck(w http.ResponseWriter, square components.Square
 	return err
 }
 
-func (m *Match) RespondWithCoverCheck(w http.ResponseWriter, tile string, t components.Square) error {
+func (m *Match) RespondWithCoverCheck(w io.Writer, tile string, t components.Square) error {
 	message := fmt.Sprintf(
 		responses.GetTileMessage(),
 		tile,
@@ -42,7 +42,7 @@ func (m *Match) RespondWithCoverCheck(w http.ResponseWriter, tile string, t comp
 	return err
 }
 
-func (m *Match) SendMessage(w http.ResponseWriter, msg string, args [2][]int) error {
+func (m *Match) SendMessage(w io.Writer, msg string, args [2][]int) error {
 	onlineGame, found := m.IsOnlineMatch()
 
 	if found && len(args) > 0 {
@@ -57,12 +57,26 @@ func (m *Match) SendMessage(w http.ResponseWriter, msg string, args [2][]int) er
 			}
 
 			newMessage := utils.ReplaceStyles(msg, bottomCoordinates, leftCoordinates)
-			onlineGame.PlayerMsg <- newMessage
-			onlineGame.Player <- onlinePlayer
+			onlineGame.Hub.Send(onlinePlayer.ID, newMessage)
+		}
+
+		if onlineGame.Spectators != nil {
+			for spectatorId, multiplier := range onlineGame.SpectatorMultipliers {
+				var bottomCoordinates []int
+				for _, coordinate := range args[0] {
+					bottomCoordinates = append(bottomCoordinates, coordinate*multiplier)
+				}
+				var leftCoordinates []int
+				for _, coordinate := range args[1] {
+					leftCoordinates = append(leftCoordinates, coordinate*multiplier)
+				}
+
+				onlineGame.Spectators.Send(spectatorId, utils.ReplaceStyles(msg, bottomCoordinates, leftCoordinates))
+			}
 		}
 
 	} else if found {
-		onlineGame.Message <- msg
+		onlineGame.Hub.Broadcast(msg)
 	} else {
 		_, err := fmt.Fprint(w, msg)
 		if err != nil {
@@ -72,7 +86,20 @@ func (m *Match) SendMessage(w http.ResponseWriter, msg string, args [2][]int) er
 	return nil
 }
 
-func (m *Match) CheckForPawnPromotion(pawnName string, w http.ResponseWriter, userId uuid.UUID) (bool, error) {
+// SendToPlayers is SendMessage for messages that only the players should get,
+// like the end of game modal, which ends the game for whoever loads it.
+func (m *Match) SendToPlayers(w io.Writer, msg string) error {
+	if onlineGame, found := m.IsOnlineMatch(); found {
+		onlineGame.Hub.Broadcast(msg)
+		return nil
+	}
+
+	_, err := fmt.Fprint(w, msg)
+
+	return err
+}
+
+func (m *Match) CheckForPawnPromotion(pawnName string, w io.Writer, userId uuid.UUID) (bool, error) {
 	var isOnLastTile bool
 	onlineGame, found := m.IsOnlineMatch()
 	pawn := m.Pieces[pawnName]
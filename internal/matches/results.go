@@ -0,0 +1,120 @@
+package matches
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+)
+
+const (
+	TerminationCheckmate            = "checkmate"
+	TerminationStalemate            = "stalemate"
+	TerminationTimeout              = "timeout"
+	TerminationResignation          = "resignation"
+	TerminationAgreement            = "agreement"
+	TerminationRepetition           = "repetition"
+	TerminationFiftyMoves           = "fifty moves"
+	TerminationInsufficientMaterial = "insufficient material"
+	TerminationAbandoned            = "abandoned"
+	TerminationAborted              = "aborted"
+)
+
+func Winner(result string) string {
+	switch result {
+	case "1-0":
+		return "white"
+	case "0-1":
+		return "black"
+	default:
+		return ""
+	}
+}
+
+// Publish sends event to every client following the match over the JSON feed.
+func (m *Match) Publish(event any) {
+	if !m.IsOnline || m.Online.Feed == nil {
+		return
+	}
+
+	msg, err := protocol.Encode(event)
+	if err != nil {
+		responses.LogError("couldn't encode feed event", err)
+		return
+	}
+
+	m.Online.Feed.Broadcast(msg)
+}
+
+func (m *Match) PublishTurn() {
+	move, ok := m.RecordMove()
+	if !ok {
+		return
+	}
+
+	m.Publish(move)
+
+	if m.IsWhiteTurn && m.IsBlackUnderCheck {
+		m.Publish(protocol.NewCheck("black", SquareName(m.Pieces["black_king"].Tile)))
+	} else if !m.IsWhiteTurn && m.IsWhiteUnderCheck {
+		m.Publish(protocol.NewCheck("white", SquareName(m.Pieces["white_king"].Tile)))
+	}
+}
+
+// PublishGameEnd records how the game ended and tells the feed. This is the
+// only place a match gets its result. Only the first call counts, so the same
+// ending reported from several places is sent and stored once.
+func (m *Match) PublishGameEnd(result, termination string) {
+	if m.Result != "" {
+		return
+	}
+
+	m.Result = result
+	m.Termination = termination
+
+	m.Publish(protocol.NewGameEnd(result, Winner(result), termination))
+	m.notifySpectatorsOfEnd(result, termination)
+}
+
+func (m *Match) GameFull(game string) protocol.GameFull {
+	white := m.Online.Players["white"]
+	black := m.Online.Players["black"]
+
+	return protocol.NewGameFull(
+		game,
+		protocol.Player{ID: white.ID.String(), Name: white.Name},
+		protocol.Player{ID: black.ID.String(), Name: black.Name},
+		m.FEN(),
+		m.colorToMove(),
+		m.UciMoves,
+		protocol.Clock{White: m.WhiteTimer, Black: m.BlackTimer},
+	)
+}
+
+// Close disconnects both the page and the JSON feed subscribers.
+func (o OnlineGame) Close() {
+	if o.Hub != nil {
+		o.Hub.Close()
+	}
+	if o.Feed != nil {
+		o.Feed.Close()
+	}
+	if o.Spectators != nil {
+		o.Spectators.Close()
+	}
+}
+
+// Shutdown closes the game's connections like Close and waits for them to
+// drain.
+func (o OnlineGame) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, h := range []*hub.Hub{o.Hub, o.Feed, o.Spectators} {
+		if h != nil {
+			errs = append(errs, h.Shutdown(ctx))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package matches
+
+import (
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// WatchPath is the shareable page where anyone can follow an online game.
+func WatchPath(game string) string {
+	return "/watch/" + strings.TrimPrefix(game, "online:")
+}
+
+// WatchGameKey turns the id from a watch link back into the match key.
+func WatchGameKey(id string) string {
+	return "online:" + id
+}
+
+func (m *Match) AddSpectator(id uuid.UUID, multiplier int) {
+	if m.Online.SpectatorMultipliers == nil {
+		m.Online.SpectatorMultipliers = make(map[uuid.UUID]int)
+	}
+
+	m.Online.SpectatorMultipliers[id] = multiplier
+}
+
+func (m *Match) RemoveSpectator(id uuid.UUID) {
+	delete(m.Online.SpectatorMultipliers, id)
+}
+
+func (m *Match) SpectatorCount() int {
+	return len(m.Online.SpectatorMultipliers)
+}
+
+// SpectatorSnapshot is a copy of the match laid out for a spectator's board,
+// with the players' clocks filled in from the match timers.
+func (m *Match) SpectatorSnapshot(multiplier int) Match {
+	snapshot := m.Clone()
+	snapshot.UpdateCoordinates(multiplier)
+
+	if snapshot.Online.Players == nil {
+		snapshot.Online.Players = make(map[string]components.OnlinePlayerStruct)
+	}
+
+	white := snapshot.Online.Players["white"]
+	white.Timer = utils.FormatTime(m.WhiteTimer)
+	snapshot.Online.Players["white"] = white
+
+	black := snapshot.Online.Players["black"]
+	black.Timer = utils.FormatTime(m.BlackTimer)
+	snapshot.Online.Players["black"] = black
+
+	return snapshot
+}
+
+// BroadcastSpectatorCount tells the players and the spectators how many
+// people are watching.
+func (m *Match) BroadcastSpectatorCount() {
+	msg, err := utils.TemplString(components.SpectatorCount(m.SpectatorCount()))
+	if err != nil {
+		responses.LogError("couldn't render spectator count", err)
+		return
+	}
+
+	if m.Online.Hub != nil {
+		m.Online.Hub.Broadcast(msg)
+	}
+	if m.Online.Spectators != nil {
+		m.Online.Spectators.Broadcast(msg)
+	}
+}
+
+func (m *Match) notifySpectatorsOfEnd(result, termination string) {
+	if !m.IsOnline || m.Online.Spectators == nil {
+		return
+	}
+
+	msg, err := utils.TemplString(components.SpectatorEndModal(result, Winner(result), termination))
+	if err != nil {
+		responses.LogError("couldn't render spectator end modal", err)
+		return
+	}
+
+	m.Online.Spectators.Broadcast(msg)
+}
@@ -0,0 +1,101 @@
+package matches
+
+import (
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestWatchPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		game     string
+		wantPath string
+	}{
+		{
+			name:     "Online game",
+			game:     "online:abc123",
+			wantPath: "/watch/abc123",
+		},
+		{
+			name:     "Bare id",
+			game:     "abc123",
+			wantPath: "/watch/abc123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WatchPath(tt.game); got != tt.wantPath {
+				t.Errorf("WatchPath() = %v, want %v", got, tt.wantPath)
+			}
+		})
+	}
+
+	if got := WatchGameKey("abc123"); got != "online:abc123" {
+		t.Errorf("WatchGameKey() = %v, want %v", got, "online:abc123")
+	}
+}
+
+func TestSpectators(t *testing.T) {
+	first := uuid.New()
+	second := uuid.New()
+
+	match := &Match{IsOnline: true}
+
+	match.AddSpectator(first, 80)
+	match.AddSpectator(second, 60)
+	match.AddSpectator(first, 100)
+
+	if got := match.SpectatorCount(); got != 2 {
+		t.Fatalf("SpectatorCount() = %v, want 2", got)
+	}
+	if got := match.Online.SpectatorMultipliers[first]; got != 100 {
+		t.Errorf("multiplier after rejoining = %v, want 100", got)
+	}
+
+	clone := match.Clone()
+	match.RemoveSpectator(first)
+
+	if got := match.SpectatorCount(); got != 1 {
+		t.Errorf("SpectatorCount() after leaving = %v, want 1", got)
+	}
+	if got := clone.SpectatorCount(); got != 2 {
+		t.Errorf("clone SpectatorCount() = %v, want 2", got)
+	}
+}
+
+func TestSpectatorSnapshot(t *testing.T) {
+	match := getMockNotationMatch()
+	match.IsOnline = true
+	match.WhiteTimer = 125
+	match.BlackTimer = 61
+	match.Online.Players = map[string]components.OnlinePlayerStruct{
+		"white": {Name: "white player", Pieces: "white"},
+		"black": {Name: "black player", Pieces: "black"},
+	}
+	match.UpdateCoordinates(80)
+
+	snapshot := match.SpectatorSnapshot(40)
+
+	square := snapshot.Board["1a"]
+	if square.Coordinates[0] != square.CoordinatePosition[0]*40 || square.Coordinates[1] != square.CoordinatePosition[1]*40 {
+		t.Errorf("snapshot coordinates = %v, want %v scaled by 40", square.Coordinates, square.CoordinatePosition)
+	}
+
+	original := match.Board["1a"]
+	if original.Coordinates[0] != original.CoordinatePosition[0]*80 || original.Coordinates[1] != original.CoordinatePosition[1]*80 {
+		t.Errorf("match coordinates changed to %v", original.Coordinates)
+	}
+
+	if got := snapshot.Online.Players["white"].Timer; got != "02:05" {
+		t.Errorf("white timer = %v, want 02:05", got)
+	}
+	if got := snapshot.Online.Players["black"].Timer; got != "01:01" {
+		t.Errorf("black timer = %v, want 01:01", got)
+	}
+	if got := match.Online.Players["white"].Timer; got != "" {
+		t.Errorf("match white timer = %v, want it untouched", got)
+	}
+}
@@ -0,0 +1,160 @@
+package matches
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoTakeback      = errors.New("there is no move to take back")
+	ErrTakebackPending = errors.New("a takeback is already requested")
+)
+
+// Position is the match as it stood before a ply, kept so the ply can be
+// taken back. The move lists only grow, so their lengths are enough to cut
+// them back, while the repetition snapshots get trimmed and are kept whole.
+type Position struct {
+	Board                 map[string]components.Square
+	Pieces                map[string]components.Piece
+	IsWhiteTurn           bool
+	IsWhiteUnderCheck     bool
+	IsBlackUnderCheck     bool
+	TilesUnderAttack      []string
+	WhiteTimer            int
+	BlackTimer            int
+	MovesSinceLastCapture int
+	PossibleEnPessant     string
+	LastPosition          map[string]components.Piece
+	LastMove              MoveCommand
+	PiecesSnapshot        []map[string]components.Piece
+	allMoves              int
+	takenWhite            int
+	takenBlack            int
+	uciMoves              int
+	sanMoves              int
+}
+
+func (m *Match) Snapshot() Position {
+	return Position{
+		Board:                 maps.Clone(m.Board),
+		Pieces:                maps.Clone(m.Pieces),
+		IsWhiteTurn:           m.IsWhiteTurn,
+		IsWhiteUnderCheck:     m.IsWhiteUnderCheck,
+		IsBlackUnderCheck:     m.IsBlackUnderCheck,
+		TilesUnderAttack:      slices.Clone(m.TilesUnderAttack),
+		WhiteTimer:            m.WhiteTimer,
+		BlackTimer:            m.BlackTimer,
+		MovesSinceLastCapture: m.MovesSinceLastCapture,
+		PossibleEnPessant:     m.PossibleEnPessant,
+		LastPosition:          maps.Clone(m.LastPosition),
+		LastMove:              m.LastMove,
+		PiecesSnapshot:        slices.Clone(m.PiecesSnapshot),
+		allMoves:              len(m.AllMoves),
+		takenWhite:            len(m.TakenPiecesWhite),
+		takenBlack:            len(m.TakenPiecesBlack),
+		uciMoves:              len(m.UciMoves),
+		sanMoves:              len(m.SanMoves),
+	}
+}
+
+// TakeBack reverts the last plies, clocks included. It changes nothing and
+// reports false if fewer plies were played.
+func (m *Match) TakeBack(plies int) bool {
+	if plies <= 0 || plies > len(m.History) {
+		return false
+	}
+
+	p := m.History[len(m.History)-plies]
+	m.History = m.History[:len(m.History)-plies]
+
+	m.Board = maps.Clone(p.Board)
+	m.Pieces = maps.Clone(p.Pieces)
+	m.SelectedPiece = components.Piece{}
+	m.IsWhiteTurn = p.IsWhiteTurn
+	m.IsWhiteUnderCheck = p.IsWhiteUnderCheck
+	m.IsBlackUnderCheck = p.IsBlackUnderCheck
+	m.TilesUnderAttack = slices.Clone(p.TilesUnderAttack)
+	m.WhiteTimer = p.WhiteTimer
+	m.BlackTimer = p.BlackTimer
+	m.MovesSinceLastCapture = p.MovesSinceLastCapture
+	m.PossibleEnPessant = p.PossibleEnPessant
+	m.LastPosition = maps.Clone(p.LastPosition)
+	m.LastMove = p.LastMove
+	m.DrawOffer = DrawOffer{}
+	m.Online.Premoves = nil
+	m.Online.PremoveFrom = nil
+	m.PiecesSnapshot = slices.Clone(p.PiecesSnapshot)
+	m.AllMoves = m.AllMoves[:p.allMoves]
+	m.TakenPiecesWhite = m.TakenPiecesWhite[:p.takenWhite]
+	m.TakenPiecesBlack = m.TakenPiecesBlack[:p.takenBlack]
+	m.UciMoves = m.UciMoves[:p.uciMoves]
+	m.SanMoves = m.SanMoves[:p.sanMoves]
+
+	m.StartTurnTimer()
+	m.UpdateCoordinates(m.CoordinateMultiplier)
+
+	return true
+}
+
+// TakebackPlies is how many plies go back so it's the requester's turn again.
+func (m *Match) TakebackPlies(requester uuid.UUID) int {
+	if m.Online.Players[m.colorToMove()].ID == requester {
+		return 2
+	}
+
+	return 1
+}
+
+// RequestTakeback records the player's request for the opponent to answer.
+func (m *Match) RequestTakeback(requester uuid.UUID) error {
+	if !m.IsPlayer(requester) {
+		return ErrNotAPlayer
+	}
+	if m.Online.TakebackBy != uuid.Nil {
+		return ErrTakebackPending
+	}
+	if m.Result != "" {
+		return ErrMatchOver
+	}
+	if m.TakebackPlies(requester) > len(m.History) {
+		return ErrNoTakeback
+	}
+
+	m.Online.TakebackBy = requester
+
+	return nil
+}
+
+// AcceptTakeback takes back the plies the opponent asked for and returns how
+// many there were.
+func (m *Match) AcceptTakeback(player uuid.UUID) (int, error) {
+	requester := m.Online.TakebackBy
+	if requester == uuid.Nil || requester == player || !m.IsPlayer(player) {
+		return 0, ErrNoTakeback
+	}
+
+	m.Online.TakebackBy = uuid.Nil
+
+	plies := m.TakebackPlies(requester)
+	if m.Result != "" || !m.TakeBack(plies) {
+		return 0, ErrNoTakeback
+	}
+
+	return plies, nil
+}
+
+// DeclineTakeback drops the request the opponent made and returns who made it.
+func (m *Match) DeclineTakeback(player uuid.UUID) (uuid.UUID, error) {
+	requester := m.Online.TakebackBy
+	if requester == uuid.Nil || requester == player || !m.IsPlayer(player) {
+		return uuid.Nil, ErrNoTakeback
+	}
+
+	m.Online.TakebackBy = uuid.Nil
+
+	return requester, nil
+}
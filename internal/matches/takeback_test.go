@@ -0,0 +1,154 @@
+package matches
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+// playMove plays a move the way the handlers do, remembering the position
+// before it.
+func playMove(m *Match, from, to string) {
+	before := m.Snapshot()
+	cmd := MoveCommand{Ply: len(m.AllMoves), From: from, To: to}
+
+	if captured := m.Board[to].Piece; captured.Name != "" {
+		if m.IsWhiteTurn {
+			m.TakenPiecesWhite = append(m.TakenPiecesWhite, captured.Image)
+		} else {
+			m.TakenPiecesBlack = append(m.TakenPiecesBlack, captured.Image)
+		}
+	}
+	movePiece(m, from, to)
+	m.AllMoves = append(m.AllMoves, to)
+	m.RecordMove()
+	if m.IsWhiteTurn {
+		m.WhiteTimer -= 5
+	} else {
+		m.BlackTimer -= 5
+	}
+	m.IsWhiteTurn = !m.IsWhiteTurn
+
+	m.RememberMove(cmd, before)
+}
+
+func TestTakeBack(t *testing.T) {
+	match := getMockNotationMatch()
+	playMove(&match, "2e", "4e")
+	playMove(&match, "7d", "5d")
+	playMove(&match, "4e", "5d")
+
+	if len(match.TakenPiecesWhite) != 1 {
+		t.Fatalf("len(TakenPiecesWhite) = %v, want 1", len(match.TakenPiecesWhite))
+	}
+
+	if !match.TakeBack(1) {
+		t.Fatalf("TakeBack(1) = false, want true")
+	}
+	if got := match.Board["5d"].Piece.Name; got != "black_pawn_4" {
+		t.Errorf("piece on 5d = %q, want black_pawn_4", got)
+	}
+	if _, ok := match.Pieces["black_pawn_4"]; !ok {
+		t.Errorf("captured pawn wasn't put back")
+	}
+	if len(match.TakenPiecesWhite) != 0 || len(match.AllMoves) != 2 || len(match.SanMoves) != 2 {
+		t.Errorf("moves weren't cut back: taken %v, moves %v, san %v", match.TakenPiecesWhite, match.AllMoves, match.SanMoves)
+	}
+	if !match.IsWhiteTurn || match.WhiteTimer != 595 {
+		t.Errorf("turn = white %v with %v left, want white with 595", match.IsWhiteTurn, match.WhiteTimer)
+	}
+
+	if !match.TakeBack(2) {
+		t.Fatalf("TakeBack(2) = false, want true")
+	}
+	if match.FEN() != StartFEN || len(match.History) != 0 || match.WhiteTimer != 600 {
+		t.Errorf("FEN() = %q with %v left, want the starting position", match.FEN(), match.WhiteTimer)
+	}
+
+	if match.TakeBack(1) {
+		t.Errorf("TakeBack(1) on the starting position = true, want false")
+	}
+}
+
+func TestRequestTakeback(t *testing.T) {
+	white := uuid.New()
+	black := uuid.New()
+
+	onlineMatch := func(moves ...[2]string) Match {
+		match := getMockNotationMatch()
+		match.IsOnline = true
+		match.Online.Players = map[string]components.OnlinePlayerStruct{
+			"white": {ID: white},
+			"black": {ID: black},
+		}
+		for _, move := range moves {
+			playMove(&match, move[0], move[1])
+		}
+
+		return match
+	}
+
+	tests := []struct {
+		name      string
+		match     Match
+		requester uuid.UUID
+		wantErr   error
+		wantPlies int
+	}{
+		{
+			name:      "Right after the own move",
+			match:     onlineMatch([2]string{"2e", "4e"}),
+			requester: white,
+			wantPlies: 1,
+		},
+		{
+			name:      "After the opponent replied",
+			match:     onlineMatch([2]string{"2e", "4e"}, [2]string{"7e", "5e"}),
+			requester: white,
+			wantPlies: 2,
+		},
+		{
+			name:      "Nothing of the own to take back",
+			match:     onlineMatch([2]string{"2e", "4e"}),
+			requester: black,
+			wantErr:   ErrNoTakeback,
+		},
+		{
+			name:      "Not a player",
+			match:     onlineMatch([2]string{"2e", "4e"}),
+			requester: uuid.New(),
+			wantErr:   ErrNotAPlayer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := tt.match
+			err := match.RequestTakeback(tt.requester)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequestTakeback() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if err := match.RequestTakeback(tt.requester); !errors.Is(err, ErrTakebackPending) {
+				t.Errorf("second RequestTakeback() error = %v, want %v", err, ErrTakebackPending)
+			}
+			if _, err := match.AcceptTakeback(tt.requester); !errors.Is(err, ErrNoTakeback) {
+				t.Errorf("AcceptTakeback() by the requester error = %v, want %v", err, ErrNoTakeback)
+			}
+
+			opponent, _ := match.Opponent(tt.requester)
+			plies, err := match.AcceptTakeback(opponent.ID)
+			if err != nil || plies != tt.wantPlies {
+				t.Fatalf("AcceptTakeback() = %v, %v, want %v", plies, err, tt.wantPlies)
+			}
+			if match.Online.Players[match.colorToMove()].ID != tt.requester {
+				t.Errorf("it's not the requester's turn after the takeback")
+			}
+		})
+	}
+}
@@ -1,15 +1,16 @@
 package matches
 
 import (
-	"net/http"
+	"io"
 	"slices"
+	"time"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
 	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
 )
 
-func (m *Match) GameDone(w http.ResponseWriter) {
+func (m *Match) GameDone(w io.Writer) {
 	var king components.Piece
 	if m.IsWhiteTurn {
 		king = m.Pieces["white_king"]
@@ -17,13 +18,14 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 		king = m.Pieces["black_king"]
 	}
 
-	if m.MovesSinceLastCapture == 50 {
-		msg, err := utils.TemplString(components.EndGameModal("1-1", "", false))
+	if m.MovesSinceLastCapture >= AutoHalfMoves {
+		m.PublishGameEnd("1-1", TerminationFiftyMoves)
+		msg, err := utils.TemplString(components.EndGameModal("1-1", "", true))
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
 		}
-		err = m.SendMessage(w, msg, [2][]int{})
+		err = m.SendToPlayers(w, msg)
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
@@ -34,13 +36,14 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 	notEnoughPieces := checkForNotEnoughPieces(m.Pieces)
 
 	if notEnoughPieces {
+		m.PublishGameEnd("1-1", TerminationInsufficientMaterial)
 		msg, err := utils.TemplString(components.EndGameModal("1-1", "", false))
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
 		}
 
-		err = m.SendMessage(w, msg, [2][]int{})
+		err = m.SendToPlayers(w, msg)
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
@@ -48,16 +51,15 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 		return
 	}
 
-	repeatingMoves := checkForRepeatingMoves(m)
-
-	if repeatingMoves {
+	if m.repetitions() >= AutoRepetitions {
+		m.PublishGameEnd("1-1", TerminationRepetition)
 		msg, err := utils.TemplString(components.EndGameModal("1-1", "", true))
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
 		}
 
-		err = m.SendMessage(w, msg, [2][]int{})
+		err = m.SendToPlayers(w, msg)
 		if err != nil {
 			responses.LogError("couldn't render end game modal", err)
 			return
@@ -91,12 +93,13 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 					}
 				}
 			}
+			m.PublishGameEnd("0-1", TerminationCheckmate)
 			msg, err := utils.TemplString(components.EndGameModal("0-1", "black", false))
 			if err != nil {
 				responses.LogError("couldn't convert component to string", err)
 				return
 			}
-			err = m.SendMessage(w, msg, [2][]int{})
+			err = m.SendToPlayers(w, msg)
 			if err != nil {
 				responses.LogError("couldn't render end game modal", err)
 				return
@@ -116,12 +119,13 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 					}
 				}
 			}
+			m.PublishGameEnd("1-0", TerminationCheckmate)
 			msg, err := utils.TemplString(components.EndGameModal("1-0", "white", false))
 			if err != nil {
 				responses.LogError("couldn't convert component to string", err)
 				return
 			}
-			err = m.SendMessage(w, msg, [2][]int{})
+			err = m.SendToPlayers(w, msg)
 			if err != nil {
 				responses.LogError("couldn't render end game modal", err)
 				return
@@ -139,12 +143,13 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 					}
 				}
 			}
+			m.PublishGameEnd("1-1", TerminationStalemate)
 			msg, err := utils.TemplString(components.EndGameModal("1-1", "", false))
 			if err != nil {
 				responses.LogError("couldn't convert component to string", err)
 				return
 			}
-			err = m.SendMessage(w, msg, [2][]int{})
+			err = m.SendToPlayers(w, msg)
 			if err != nil {
 				responses.LogError("couldn't render end game modal", err)
 				return
@@ -162,12 +167,13 @@ func (m *Match) GameDone(w http.ResponseWriter) {
 					}
 				}
 			}
+			m.PublishGameEnd("1-1", TerminationStalemate)
 			msg, err := utils.TemplString(components.EndGameModal("1-1", "", false))
 			if err != nil {
 				responses.LogError("couldn't convert component to string", err)
 				return
 			}
-			err = m.SendMessage(w, msg, [2][]int{})
+			err = m.SendToPlayers(w, msg)
 			if err != nil {
 				responses.LogError("couldn't render end game modal", err)
 				return
@@ -192,14 +198,65 @@ func (m *Match) BigCleanup(currentSquareName string) {
 	m.Board[currentSquareName] = currentSquare
 }
 
-func (m *Match) EndTurn(w http.ResponseWriter) {
+func (m *Match) EndTurn(w io.Writer) {
 	if m.IsWhiteTurn {
 		m.WhiteTimer += m.Addition
 	} else {
 		m.BlackTimer += m.Addition
 	}
+	mover := m.colorToMove()
+	m.compensateLag(mover, m.Lag(mover))
+	before := m.LastPosition
+	if before == nil {
+		before = MakePieces()
+	}
+	recorded := len(m.SanMoves)
+	m.PublishTurn()
+
+	var san string
+	if len(m.SanMoves) > recorded {
+		san = m.SanMoves[len(m.SanMoves)-1]
+	}
+	m.countPly(san, before)
+
 	m.IsWhiteTurn = !m.IsWhiteTurn
+	m.StartTurnTimer()
 	m.GameDone(w)
+	m.updateDraws(w, mover)
+	m.dropAbort(w)
+	m.SendLag(w)
+}
+
+func (m *Match) TickTimer() {
+	if m.IsWhiteTurn {
+		m.WhiteTimer -= 1
+	} else {
+		m.BlackTimer -= 1
+	}
+}
+
+func (m *Match) StartTurnTimer() {
+	m.TurnStarted = time.Now()
+	if m.IsWhiteTurn {
+		m.TurnStartTimer = m.WhiteTimer
+	} else {
+		m.TurnStartTimer = m.BlackTimer
+	}
+}
+
+func (m *Match) TimeSpentOnTurn() int {
+	var spent int
+	if m.IsWhiteTurn {
+		spent = m.TurnStartTimer - m.WhiteTimer
+	} else {
+		spent = m.TurnStartTimer - m.BlackTimer
+	}
+
+	if spent < 0 {
+		return 0
+	}
+
+	return spent
 }
 
 func (m *Match) EatCleanup(pieceToDelete components.Piece, squareToDeleteName, currentSquareName string) (components.Square, components.Piece) {
@@ -1,20 +1,27 @@
 package matches
 
 import (
+	"time"
+
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
-	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/google/uuid"
 )
 
 type OnlineGame struct {
-	Players      map[string]components.OnlinePlayerStruct
-	Message      chan (string)
-	PlayerMsg    chan (string)
-	Player       chan (components.OnlinePlayerStruct)
-	PlayersQueue queue.PlayersQueue
-}
-
-type Matches struct {
-	Matches map[string]Match
+	Players              map[string]components.OnlinePlayerStruct
+	Hub                  *hub.Hub
+	Feed                 *hub.Hub
+	Spectators           *hub.Hub
+	SpectatorMultipliers map[uuid.UUID]int
+	Disconnected         map[uuid.UUID]time.Time
+	TakebackBy           uuid.UUID
+	FirstMoveTimeout     time.Duration
+	Premoves             map[string][]Premove
+	PremoveFrom          map[string]string
+	LagCredit            map[string]time.Duration
+	Chat                 *chat.Room
 }
 
 type Match struct {
@@ -28,14 +35,27 @@ type Match struct {
 	TilesUnderAttack      []string
 	BlackTimer            int
 	WhiteTimer            int
+	TurnStartTimer        int
+	TurnStarted           time.Time
+	FullTime              int
 	Addition              int
+	Rated                 bool
 	AllMoves              []string
 	PiecesSnapshot        []map[string]components.Piece
 	MatchId               int32
-	MovesSinceLastCapture int8
+	MovesSinceLastCapture int
 	PossibleEnPessant     string
 	TakenPiecesWhite      []string
 	TakenPiecesBlack      []string
 	IsOnline              bool
 	Online                OnlineGame
+	LastPosition          map[string]components.Piece
+	UciMoves              []string
+	SanMoves              []string
+	Result                string
+	Termination           string
+	LastMove              MoveCommand
+	History               []Position
+	DrawOffer             DrawOffer
+	LastDrawOffers        map[string]int
 }
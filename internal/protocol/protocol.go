@@ -0,0 +1,181 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version is sent with every event. Clients should refuse to play on a
+// version they don't know.
+const Version = 1
+
+const (
+	EventGameFull  = "gameFull"
+	EventMove      = "move"
+	EventCheck     = "check"
+	EventGameEnd   = "gameEnd"
+	EventDrawOffer = "drawOffer"
+	EventTakeback  = "takeback"
+	EventChat      = "chat"
+	EventError     = "error"
+)
+
+const (
+	CommandMove      = "move"
+	CommandResign    = "resign"
+	CommandAbort     = "abort"
+	CommandOfferDraw = "offerDraw"
+	CommandClaimDraw = "claimDraw"
+	CommandChat      = "chat"
+)
+
+type Player struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Clock struct {
+	White int `json:"white"`
+	Black int `json:"black"`
+}
+
+type GameFull struct {
+	Type  string   `json:"type"`
+	V     int      `json:"v"`
+	Game  string   `json:"game"`
+	White Player   `json:"white"`
+	Black Player   `json:"black"`
+	Fen   string   `json:"fen"`
+	Turn  string   `json:"turn"`
+	Moves []string `json:"moves"`
+	Clock Clock    `json:"clock"`
+}
+
+type Move struct {
+	Type  string `json:"type"`
+	V     int    `json:"v"`
+	Ply   int    `json:"ply"`
+	San   string `json:"san"`
+	Uci   string `json:"uci"`
+	Clock Clock  `json:"clock"`
+}
+
+type Check struct {
+	Type   string `json:"type"`
+	V      int    `json:"v"`
+	Color  string `json:"color"`
+	Square string `json:"square"`
+}
+
+type GameEnd struct {
+	Type        string `json:"type"`
+	V           int    `json:"v"`
+	Result      string `json:"result"`
+	Winner      string `json:"winner,omitempty"`
+	Termination string `json:"termination"`
+}
+
+type DrawOffer struct {
+	Type string `json:"type"`
+	V    int    `json:"v"`
+	By   string `json:"by"`
+}
+
+type Takeback struct {
+	Type  string `json:"type"`
+	V     int    `json:"v"`
+	Ply   int    `json:"ply"`
+	Clock Clock  `json:"clock"`
+}
+
+type Chat struct {
+	Type string `json:"type"`
+	V    int    `json:"v"`
+	User string `json:"user"`
+	Text string `json:"text"`
+}
+
+type Error struct {
+	Type    string `json:"type"`
+	V       int    `json:"v"`
+	Message string `json:"message"`
+}
+
+type Command struct {
+	Type string `json:"type"`
+	V    int    `json:"v"`
+	Uci  string `json:"uci,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+func NewGameFull(game string, white, black Player, fen, turn string, moves []string, clock Clock) GameFull {
+	if moves == nil {
+		moves = []string{}
+	}
+
+	return GameFull{Type: EventGameFull, V: Version, Game: game, White: white, Black: black, Fen: fen, Turn: turn, Moves: moves, Clock: clock}
+}
+
+func NewMove(ply int, san, uci string, clock Clock) Move {
+	return Move{Type: EventMove, V: Version, Ply: ply, San: san, Uci: uci, Clock: clock}
+}
+
+func NewCheck(color, square string) Check {
+	return Check{Type: EventCheck, V: Version, Color: color, Square: square}
+}
+
+func NewGameEnd(result, winner, termination string) GameEnd {
+	return GameEnd{Type: EventGameEnd, V: Version, Result: result, Winner: winner, Termination: termination}
+}
+
+func NewDrawOffer(by string) DrawOffer {
+	return DrawOffer{Type: EventDrawOffer, V: Version, By: by}
+}
+
+func NewTakeback(ply int, clock Clock) Takeback {
+	return Takeback{Type: EventTakeback, V: Version, Ply: ply, Clock: clock}
+}
+
+func NewChat(user, text string) Chat {
+	return Chat{Type: EventChat, V: Version, User: user, Text: text}
+}
+
+func NewError(message string) Error {
+	return Error{Type: EventError, V: Version, Message: message}
+}
+
+func Encode(event any) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func DecodeCommand(data []byte) (Command, error) {
+	var cmd Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return Command{}, err
+	}
+
+	if cmd.V != Version {
+		return Command{}, fmt.Errorf("unsupported protocol version %v", cmd.V)
+	}
+
+	switch cmd.Type {
+	case CommandMove:
+		if len(cmd.Uci) != 4 && len(cmd.Uci) != 5 {
+			return Command{}, fmt.Errorf("invalid uci move %q", cmd.Uci)
+		}
+	case CommandChat:
+		if cmd.Text == "" {
+			return Command{}, fmt.Errorf("chat command without text")
+		}
+	case CommandResign, CommandAbort, CommandOfferDraw, CommandClaimDraw:
+	default:
+		return Command{}, fmt.Errorf("unknown command %q", cmd.Type)
+	}
+
+	return cmd, nil
+}
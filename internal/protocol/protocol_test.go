@@ -0,0 +1,129 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestDecodeCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Command
+		wantErr bool
+	}{
+		{
+			name: "Move",
+			data: `{"v": 1, "type": "move", "uci": "e2e4"}`,
+			want: Command{V: 1, Type: CommandMove, Uci: "e2e4"},
+		},
+		{
+			name: "Promotion",
+			data: `{"v": 1, "type": "move", "uci": "a7a8q"}`,
+			want: Command{V: 1, Type: CommandMove, Uci: "a7a8q"},
+		},
+		{
+			name: "Resign",
+			data: `{"v": 1, "type": "resign"}`,
+			want: Command{V: 1, Type: CommandResign},
+		},
+		{
+			name: "Abort",
+			data: `{"v": 1, "type": "abort"}`,
+			want: Command{V: 1, Type: CommandAbort},
+		},
+		{
+			name: "Offer draw",
+			data: `{"v": 1, "type": "offerDraw"}`,
+			want: Command{V: 1, Type: CommandOfferDraw},
+		},
+		{
+			name: "Claim draw",
+			data: `{"v": 1, "type": "claimDraw"}`,
+			want: Command{V: 1, Type: CommandClaimDraw},
+		},
+		{
+			name: "Chat",
+			data: `{"v": 1, "type": "chat", "text": "good luck"}`,
+			want: Command{V: 1, Type: CommandChat, Text: "good luck"},
+		},
+		{
+			name:    "Chat without text",
+			data:    `{"v": 1, "type": "chat"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Move without uci",
+			data:    `{"v": 1, "type": "move"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown version",
+			data:    `{"v": 2, "type": "resign"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown command",
+			data:    `{"v": 1, "type": "pass"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Not json",
+			data:    `<div></div>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCommand([]byte(tt.data))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeCommand() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		event any
+		want  string
+	}{
+		{
+			name:  "Move",
+			event: NewMove(1, "e4", "e2e4", Clock{White: 598, Black: 600}),
+			want:  `{"type":"move","v":1,"ply":1,"san":"e4","uci":"e2e4","clock":{"white":598,"black":600}}`,
+		},
+		{
+			name:  "Draw game end has no winner",
+			event: NewGameEnd("1-1", "", "agreement"),
+			want:  `{"type":"gameEnd","v":1,"result":"1-1","termination":"agreement"}`,
+		},
+		{
+			name:  "Takeback",
+			event: NewTakeback(2, Clock{White: 595, Black: 597}),
+			want:  `{"type":"takeback","v":1,"ply":2,"clock":{"white":595,"black":597}}`,
+		},
+		{
+			name:  "Game full without moves",
+			event: NewGameFull("online:x", Player{ID: "1", Name: "a"}, Player{ID: "2", Name: "b"}, "fen", "white", nil, Clock{}),
+			want:  `{"type":"gameFull","v":1,"game":"online:x","white":{"id":"1","name":"a"},"black":{"id":"2","name":"b"},"fen":"fen","turn":"white","moves":[],"clock":{"white":0,"black":0}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.event)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
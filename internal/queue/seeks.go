@@ -0,0 +1,312 @@
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+const VariantStandard = "standard"
+
+const (
+	ColorWhite  = "white"
+	ColorBlack  = "black"
+	ColorRandom = "random"
+)
+
+const (
+	// SeekTimeout is how long a seek waits for its player to connect before
+	// the matchmaker drops it.
+	SeekTimeout = 10 * time.Second
+
+	// The rating range a seek accepts starts at baseWindow and widens by
+	// windowStep every windowInterval spent waiting, up to maxWindow.
+	baseWindow     = 100.0
+	windowStep     = 50.0
+	windowInterval = 5 * time.Second
+	maxWindow      = 700.0
+)
+
+// Pool is what two seeks need to agree on to be paired.
+type Pool struct {
+	FullTime int
+	Addition int
+	Variant  string
+	Rated    bool
+}
+
+// ParsePool reads a time control in the "600+3" form the time picker uses.
+func ParsePool(duration string, rated bool) (Pool, error) {
+	fullTime, addition, found := strings.Cut(duration, "+")
+	if !found {
+		return Pool{}, fmt.Errorf("invalid time control %q", duration)
+	}
+
+	seconds, err := strconv.Atoi(fullTime)
+	if err != nil || seconds <= 0 {
+		return Pool{}, fmt.Errorf("invalid time control %q", duration)
+	}
+
+	increment, err := strconv.Atoi(addition)
+	if err != nil || increment < 0 {
+		return Pool{}, fmt.Errorf("invalid time control %q", duration)
+	}
+
+	return Pool{
+		FullTime: seconds,
+		Addition: increment,
+		Variant:  VariantStandard,
+		Rated:    rated,
+	}, nil
+}
+
+func (p Pool) String() string {
+	mode := "casual"
+	if p.Rated {
+		mode = "rated"
+	}
+
+	return fmt.Sprintf("%v+%v %v", p.FullTime/60, p.Addition, mode)
+}
+
+// Seek is a player waiting for an opponent from the same pool, wanting to
+// play Color.
+type Seek struct {
+	ID          string
+	Player      components.OnlinePlayerStruct
+	Pool        Pool
+	Color       string
+	Rating      float64
+	Provisional bool
+	Since       time.Time
+}
+
+// Compatible reports whether the two seeks don't both want the same color.
+func (s Seek) Compatible(other Seek) bool {
+	return s.Color == ColorRandom || s.Color == "" || s.Color != other.Color
+}
+
+// Seats gives both players their colors. The seek's own preference comes
+// first, then the opponent's, and whiteFirst settles it when neither cares.
+func (s Seek) Seats(opponent Seek, whiteFirst bool) (white, black components.OnlinePlayerStruct) {
+	seekWhite := whiteFirst
+	switch {
+	case s.Color == ColorWhite:
+		seekWhite = true
+	case s.Color == ColorBlack:
+		seekWhite = false
+	case opponent.Color == ColorWhite:
+		seekWhite = false
+	case opponent.Color == ColorBlack:
+		seekWhite = true
+	}
+
+	if seekWhite {
+		return s.Player, opponent.Player
+	}
+
+	return opponent.Player, s.Player
+}
+
+// Window is how far from its rating the seek looks for an opponent.
+func (s Seek) Window(now time.Time) float64 {
+	steps := math.Floor(float64(now.Sub(s.Since)) / float64(windowInterval))
+
+	return math.Min(baseWindow+windowStep*math.Max(steps, 0), maxWindow)
+}
+
+type Pair struct {
+	First  Seek
+	Second Seek
+}
+
+// SeekPool holds the open seeks of every pool, one per player, oldest first.
+type SeekPool struct {
+	mu    sync.Mutex
+	seeks map[Pool][]Seek
+}
+
+func NewSeekPool() *SeekPool {
+	return &SeekPool{seeks: make(map[Pool][]Seek)}
+}
+
+// Add puts the seek in its pool, replacing any other seek of the player. A
+// seek that already has an ID and Since keeps them, and its place in the queue.
+func (s *SeekPool) Add(seek Seek, now time.Time) (Seek, error) {
+	if seek.ID == "" {
+		token := make([]byte, 8)
+		if _, err := rand.Read(token); err != nil {
+			return Seek{}, err
+		}
+		seek.ID = hex.EncodeToString(token)
+	}
+
+	if seek.Since.IsZero() {
+		seek.Since = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(seek.Player.ID)
+
+	seeks := append(s.seeks[seek.Pool], seek)
+	slices.SortStableFunc(seeks, func(a, b Seek) int {
+		return a.Since.Compare(b.Since)
+	})
+	s.seeks[seek.Pool] = seeks
+
+	return seek, nil
+}
+
+func (s *SeekPool) Remove(player uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(player)
+}
+
+func (s *SeekPool) Get(player uuid.UUID) (Seek, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, seeks := range s.seeks {
+		for _, seek := range seeks {
+			if seek.Player.ID == player {
+				return seek, true
+			}
+		}
+	}
+
+	return Seek{}, false
+}
+
+// Take removes the seek so only one player can answer it.
+func (s *SeekPool) Take(id string) (Seek, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, seeks := range s.seeks {
+		for _, seek := range seeks {
+			if seek.ID == id {
+				s.remove(seek.Player.ID)
+				return seek, true
+			}
+		}
+	}
+
+	return Seek{}, false
+}
+
+// All lists every open seek, longest waiting first.
+func (s *SeekPool) All() []Seek {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var all []Seek
+	for _, seeks := range s.seeks {
+		all = append(all, seeks...)
+	}
+
+	slices.SortStableFunc(all, func(a, b Seek) int {
+		return a.Since.Compare(b.Since)
+	})
+
+	return all
+}
+
+func (s *SeekPool) Len(pool Pool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.seeks[pool])
+}
+
+// Pairs takes every seek that has an acceptable opponent out of the pool.
+// Going from the longest waiting seek, each is paired with the closest rated
+// compatible seek inside both of their windows.
+func (s *SeekPool) Pairs(now time.Time) []Pair {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pairs []Pair
+
+	for pool, seeks := range s.seeks {
+		paired := make([]bool, len(seeks))
+
+		for i, seek := range seeks {
+			if paired[i] {
+				continue
+			}
+
+			best := -1
+			bestDiff := math.Inf(1)
+			for j := i + 1; j < len(seeks); j++ {
+				if paired[j] || !seek.Compatible(seeks[j]) {
+					continue
+				}
+
+				diff := math.Abs(seek.Rating - seeks[j].Rating)
+				if diff > seek.Window(now) || diff > seeks[j].Window(now) {
+					continue
+				}
+
+				if diff < bestDiff {
+					best = j
+					bestDiff = diff
+				}
+			}
+
+			if best == -1 {
+				continue
+			}
+
+			paired[i] = true
+			paired[best] = true
+			pairs = append(pairs, Pair{First: seek, Second: seeks[best]})
+		}
+
+		var waiting []Seek
+		for i, seek := range seeks {
+			if !paired[i] {
+				waiting = append(waiting, seek)
+			}
+		}
+
+		if len(waiting) == 0 {
+			delete(s.seeks, pool)
+		} else {
+			s.seeks[pool] = waiting
+		}
+	}
+
+	return pairs
+}
+
+func (s *SeekPool) remove(player uuid.UUID) bool {
+	for pool, seeks := range s.seeks {
+		for i, seek := range seeks {
+			if seek.Player.ID != player {
+				continue
+			}
+
+			if len(seeks) == 1 {
+				delete(s.seeks, pool)
+			} else {
+				s.seeks[pool] = append(seeks[:i:i], seeks[i+1:]...)
+			}
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,297 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/google/uuid"
+)
+
+func TestParsePool(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration string
+		rated    bool
+		want     Pool
+		wantErr  bool
+	}{
+		{
+			name:     "Ten minutes",
+			duration: "600+0",
+			want:     Pool{FullTime: 600, Variant: VariantStandard},
+		},
+		{
+			name:     "Rated three plus two",
+			duration: "180+2",
+			rated:    true,
+			want:     Pool{FullTime: 180, Addition: 2, Variant: VariantStandard, Rated: true},
+		},
+		{
+			name:     "Missing addition",
+			duration: "600",
+			wantErr:  true,
+		},
+		{
+			name:     "No time",
+			duration: "0+3",
+			wantErr:  true,
+		},
+		{
+			name:     "Not a number",
+			duration: "ten+0",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePool(tt.duration, tt.rated)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePool() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeekWindow(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		waited time.Duration
+		want   float64
+	}{
+		{name: "Just created", waited: 0, want: 100},
+		{name: "Waited a bit", waited: 4 * time.Second, want: 100},
+		{name: "Widens every five seconds", waited: 12 * time.Second, want: 200},
+		{name: "Stops widening", waited: time.Hour, want: 700},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seek := Seek{Since: now.Add(-tt.waited)}
+			if got := seek.Window(now); got != tt.want {
+				t.Errorf("Window() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeekPoolPairs(t *testing.T) {
+	blitz := Pool{FullTime: 180, Addition: 2, Variant: VariantStandard}
+	rapid := Pool{FullTime: 600, Variant: VariantStandard}
+	now := time.Now()
+
+	seek := func(name string, pool Pool, rating float64, waited time.Duration) Seek {
+		return Seek{
+			Player: components.OnlinePlayerStruct{ID: uuid.New(), Name: name},
+			Pool:   pool,
+			Color:  ColorRandom,
+			Rating: rating,
+			Since:  now.Add(-waited),
+		}
+	}
+	withColor := func(s Seek, color string) Seek {
+		s.Color = color
+		return s
+	}
+
+	tests := []struct {
+		name        string
+		seeks       []Seek
+		wantPairs   [][2]string
+		wantWaiting int
+	}{
+		{
+			name:        "Alone in the pool",
+			seeks:       []Seek{seek("a", blitz, 1500, 0)},
+			wantWaiting: 1,
+		},
+		{
+			name: "Close ratings pair",
+			seeks: []Seek{
+				seek("a", blitz, 1500, time.Second),
+				seek("b", blitz, 1550, 0),
+			},
+			wantPairs: [][2]string{{"a", "b"}},
+		},
+		{
+			name: "Different time controls don't pair",
+			seeks: []Seek{
+				seek("a", blitz, 1500, time.Second),
+				seek("b", rapid, 1500, 0),
+			},
+			wantWaiting: 2,
+		},
+		{
+			name: "Too far apart at first",
+			seeks: []Seek{
+				seek("a", blitz, 1500, time.Second),
+				seek("b", blitz, 1800, 0),
+			},
+			wantWaiting: 2,
+		},
+		{
+			name: "Both windows widened enough",
+			seeks: []Seek{
+				seek("a", blitz, 1500, 30*time.Second),
+				seek("b", blitz, 1800, 20*time.Second),
+			},
+			wantPairs: [][2]string{{"a", "b"}},
+		},
+		{
+			name: "Only one window widened enough",
+			seeks: []Seek{
+				seek("a", blitz, 1500, time.Minute),
+				seek("b", blitz, 1800, 0),
+			},
+			wantWaiting: 2,
+		},
+		{
+			name: "Longest waiting gets the closest opponent",
+			seeks: []Seek{
+				seek("a", blitz, 1500, 3*time.Second),
+				seek("b", blitz, 1580, 2*time.Second),
+				seek("c", blitz, 1510, time.Second),
+			},
+			wantPairs:   [][2]string{{"a", "c"}},
+			wantWaiting: 1,
+		},
+		{
+			name: "Both want white",
+			seeks: []Seek{
+				withColor(seek("a", blitz, 1500, time.Second), ColorWhite),
+				withColor(seek("b", blitz, 1500, 0), ColorWhite),
+			},
+			wantWaiting: 2,
+		},
+		{
+			name: "Skips a conflicting color for a farther opponent",
+			seeks: []Seek{
+				withColor(seek("a", blitz, 1500, 3*time.Second), ColorBlack),
+				withColor(seek("b", blitz, 1510, 2*time.Second), ColorBlack),
+				seek("c", blitz, 1560, time.Second),
+			},
+			wantPairs:   [][2]string{{"a", "c"}},
+			wantWaiting: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NewSeekPool()
+			for _, s := range tt.seeks {
+				pool.Add(s, now)
+			}
+
+			pairs := pool.Pairs(now)
+			if len(pairs) != len(tt.wantPairs) {
+				t.Fatalf("Pairs() = %v pairs, want %v", len(pairs), len(tt.wantPairs))
+			}
+			for i, pair := range pairs {
+				got := [2]string{pair.First.Player.Name, pair.Second.Player.Name}
+				if got != tt.wantPairs[i] {
+					t.Errorf("pair %v = %v, want %v", i, got, tt.wantPairs[i])
+				}
+			}
+
+			if waiting := pool.Len(blitz) + pool.Len(rapid); waiting != tt.wantWaiting {
+				t.Errorf("waiting = %v, want %v", waiting, tt.wantWaiting)
+			}
+		})
+	}
+}
+
+func TestSeekPoolAdd(t *testing.T) {
+	blitz := Pool{FullTime: 180, Addition: 2, Variant: VariantStandard}
+	rapid := Pool{FullTime: 600, Variant: VariantStandard}
+	player := components.OnlinePlayerStruct{ID: uuid.New()}
+	now := time.Now()
+
+	pool := NewSeekPool()
+	first, err := pool.Add(Seek{Player: player, Pool: blitz}, now)
+	if err != nil || first.ID == "" {
+		t.Fatalf("Add() = %v, %v, want a seek with an ID", first, err)
+	}
+	pool.Add(Seek{Player: player, Pool: rapid}, now.Add(time.Second))
+
+	if pool.Len(blitz) != 0 || pool.Len(rapid) != 1 {
+		t.Errorf("a new seek didn't replace the old one, Len() = %v and %v", pool.Len(blitz), pool.Len(rapid))
+	}
+
+	seek, ok := pool.Get(player.ID)
+	if !ok || !seek.Since.Equal(now.Add(time.Second)) {
+		t.Errorf("Get() = %v, %v, want the rapid seek", seek, ok)
+	}
+
+	older := Seek{Player: components.OnlinePlayerStruct{ID: uuid.New()}, Pool: rapid, Since: now.Add(-time.Minute)}
+	pool.Add(older, now)
+	if seek, _ := pool.Get(older.Player.ID); !seek.Since.Equal(older.Since) {
+		t.Errorf("re-added seek lost its place, Since = %v, want %v", seek.Since, older.Since)
+	}
+
+	if !pool.Remove(player.ID) {
+		t.Errorf("Remove() = false, want true")
+	}
+	if pool.Remove(player.ID) {
+		t.Errorf("Remove() twice = true, want false")
+	}
+}
+
+func TestSeekPoolTake(t *testing.T) {
+	blitz := Pool{FullTime: 180, Addition: 2, Variant: VariantStandard}
+	rapid := Pool{FullTime: 600, Variant: VariantStandard}
+	now := time.Now()
+
+	pool := NewSeekPool()
+	newer, _ := pool.Add(Seek{Player: components.OnlinePlayerStruct{ID: uuid.New()}, Pool: blitz}, now)
+	older, _ := pool.Add(Seek{Player: components.OnlinePlayerStruct{ID: uuid.New()}, Pool: rapid}, now.Add(-time.Minute))
+
+	all := pool.All()
+	if len(all) != 2 || all[0].ID != older.ID || all[1].ID != newer.ID {
+		t.Fatalf("All() = %v, want the older seek first", all)
+	}
+
+	if got, ok := pool.Take(newer.ID); !ok || got.Player.ID != newer.Player.ID {
+		t.Errorf("Take() = %v, %v, want the blitz seek", got, ok)
+	}
+	if _, ok := pool.Take(newer.ID); ok {
+		t.Errorf("Take() twice = true, want false")
+	}
+	if len(pool.All()) != 1 {
+		t.Errorf("All() after Take() = %v, want one seek", pool.All())
+	}
+}
+
+func TestSeekSeats(t *testing.T) {
+	seek := func(color string) Seek {
+		return Seek{Player: components.OnlinePlayerStruct{Name: color}, Color: color}
+	}
+
+	tests := []struct {
+		name       string
+		seek       Seek
+		opponent   Seek
+		whiteFirst bool
+		wantWhite  string
+	}{
+		{name: "Wants white", seek: seek(ColorWhite), opponent: seek(ColorRandom), wantWhite: ColorWhite},
+		{name: "Wants black", seek: seek(ColorBlack), opponent: seek(ColorRandom), whiteFirst: true, wantWhite: ColorRandom},
+		{name: "Opponent wants white", seek: seek(ColorRandom), opponent: seek(ColorWhite), whiteFirst: true, wantWhite: ColorWhite},
+		{name: "Opponent wants black", seek: seek(ColorRandom), opponent: seek(ColorBlack), wantWhite: ColorRandom},
+		{name: "Nobody cares", seek: seek(ColorRandom), opponent: seek(""), whiteFirst: false, wantWhite: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			white, black := tt.seek.Seats(tt.opponent, tt.whiteFirst)
+			if white.Name != tt.wantWhite || white == black {
+				t.Errorf("Seats() white = %q, want %q", white.Name, tt.wantWhite)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package ratings
+
+import "math"
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+
+	// ProvisionalDeviation is the deviation above which a rating isn't
+	// trusted yet and is shown with a question mark.
+	ProvisionalDeviation = 110.0
+
+	tau     = 0.5
+	scale   = 173.7178
+	epsilon = 0.000001
+)
+
+const (
+	CategoryBullet    = "bullet"
+	CategoryBlitz     = "blitz"
+	CategoryRapid     = "rapid"
+	CategoryClassical = "classical"
+)
+
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+}
+
+func Default() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+func (r Rating) Provisional() bool {
+	return r.Deviation > ProvisionalDeviation
+}
+
+// Result is one game of the rating period, Score being 1 for a win, 0.5 for
+// a draw and 0 for a loss.
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Category groups time controls by the estimated length of a game, counting
+// 40 moves worth of addition.
+func Category(fullTime, addition int) string {
+	estimated := fullTime + 40*addition
+
+	switch {
+	case estimated < 180:
+		return CategoryBullet
+	case estimated < 480:
+		return CategoryBlitz
+	case estimated < 1500:
+		return CategoryRapid
+	default:
+		return CategoryClassical
+	}
+}
+
+// Scores turns a game result into the score of each side. Unfinished and
+// aborted games don't score.
+func Scores(result string) (white, black float64, ok bool) {
+	switch result {
+	case "1-0":
+		return 1, 0, true
+	case "0-1":
+		return 0, 1, true
+	case "1-1":
+		return 0.5, 0.5, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// Update applies one rating period to the rating, following Glickman's
+// Glicko-2 paper.
+func Update(r Rating, results []Result) Rating {
+	mu := (r.Rating - DefaultRating) / scale
+	phi := r.Deviation / scale
+
+	if len(results) == 0 {
+		phiStar := math.Sqrt(phi*phi + r.Volatility*r.Volatility)
+		return Rating{
+			Rating:     r.Rating,
+			Deviation:  math.Min(phiStar*scale, DefaultDeviation),
+			Volatility: r.Volatility,
+		}
+	}
+
+	var vInv, sum float64
+	for _, result := range results {
+		muJ := (result.Opponent.Rating - DefaultRating) / scale
+		gJ := g(result.Opponent.Deviation / scale)
+		eJ := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+
+		vInv += gJ * gJ * eJ * (1 - eJ)
+		sum += gJ * (result.Score - eJ)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma := volatility(phi, v, delta, r.Volatility)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*sum
+
+	return Rating{
+		Rating:     newMu*scale + DefaultRating,
+		Deviation:  math.Min(newPhi*scale, DefaultDeviation),
+		Volatility: sigma,
+	}
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func volatility(phi, v, delta, sigma float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,111 @@
+package ratings
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		rating         Rating
+		results        []Result
+		wantRating     float64
+		wantDeviation  float64
+		wantVolatility float64
+	}{
+		{
+			name:   "Example from the Glicko-2 paper",
+			rating: Rating{Rating: 1500, Deviation: 200, Volatility: 0.06},
+			results: []Result{
+				{Opponent: Rating{Rating: 1400, Deviation: 30, Volatility: 0.06}, Score: 1},
+				{Opponent: Rating{Rating: 1550, Deviation: 100, Volatility: 0.06}, Score: 0},
+				{Opponent: Rating{Rating: 1700, Deviation: 300, Volatility: 0.06}, Score: 0},
+			},
+			wantRating:     1464.06,
+			wantDeviation:  151.52,
+			wantVolatility: 0.05999,
+		},
+		{
+			name:           "No games only widens the deviation",
+			rating:         Rating{Rating: 1500, Deviation: 200, Volatility: 0.06},
+			wantRating:     1500,
+			wantDeviation:  200.27,
+			wantVolatility: 0.06,
+		},
+		{
+			name:   "New players win a lot from a draw with a stronger player",
+			rating: Default(),
+			results: []Result{
+				{Opponent: Rating{Rating: 1800, Deviation: 60, Volatility: 0.06}, Score: 0.5},
+			},
+			wantRating:     1658.10,
+			wantDeviation:  284.64,
+			wantVolatility: 0.06,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Update(tt.rating, tt.results)
+
+			if math.Abs(got.Rating-tt.wantRating) > 0.01 {
+				t.Errorf("Rating = %.2f, want %.2f", got.Rating, tt.wantRating)
+			}
+			if math.Abs(got.Deviation-tt.wantDeviation) > 0.01 {
+				t.Errorf("Deviation = %.2f, want %.2f", got.Deviation, tt.wantDeviation)
+			}
+			if math.Abs(got.Volatility-tt.wantVolatility) > 0.00001 {
+				t.Errorf("Volatility = %.5f, want %.5f", got.Volatility, tt.wantVolatility)
+			}
+		})
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		fullTime int
+		addition int
+		want     string
+	}{
+		{name: "One minute", fullTime: 60, want: CategoryBullet},
+		{name: "Two plus one", fullTime: 120, addition: 1, want: CategoryBullet},
+		{name: "Three plus two", fullTime: 180, addition: 2, want: CategoryBlitz},
+		{name: "Ten minutes", fullTime: 600, want: CategoryRapid},
+		{name: "Fifteen plus ten", fullTime: 900, addition: 10, want: CategoryRapid},
+		{name: "Thirty minutes", fullTime: 1800, want: CategoryClassical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Category(tt.fullTime, tt.addition); got != tt.want {
+				t.Errorf("Category() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	tests := []struct {
+		result    string
+		wantWhite float64
+		wantBlack float64
+		wantOk    bool
+	}{
+		{result: "1-0", wantWhite: 1, wantBlack: 0, wantOk: true},
+		{result: "0-1", wantWhite: 0, wantBlack: 1, wantOk: true},
+		{result: "1-1", wantWhite: 0.5, wantBlack: 0.5, wantOk: true},
+		{result: "*"},
+		{result: "0-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			white, black, ok := Scores(tt.result)
+			if white != tt.wantWhite || black != tt.wantBlack || ok != tt.wantOk {
+				t.Errorf("Scores() = %v, %v, %v, want %v, %v, %v", white, black, ok, tt.wantWhite, tt.wantBlack, tt.wantOk)
+			}
+		})
+	}
+}
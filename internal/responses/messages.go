@@ -125,6 +125,13 @@ func GetMovesNumberUpdateMessage() string {
 	`
 }
 
+func GetMoveStateMessage() string {
+	return `
+		<input type="hidden" id="move-ply" name="ply" hx-swap-oob="true" value="%v" />
+		<input type="hidden" id="move-from" name="from" hx-swap-oob="true" value="%v" />
+	`
+}
+
 func GetTimePicker() string {
 	return `
 		<div class="absolute right-0 mt-2 w-48 bg-[#1e1c1a] border border-[#3a3733] text-white rounded-md shadow-lg z-50">
@@ -2,10 +2,10 @@ package responses
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"runtime"
-	"strconv"
 	"strings"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
@@ -41,21 +41,8 @@ func LogError(message string, err error) {
 	log.Printf("%v -> %v:%v\n", caller, message, err)
 }
 
-func RespondWithNewPiece(w http.ResponseWriter, r *http.Request, square components.Square) error {
-	err := r.ParseForm()
-
-	if err != nil {
-		RespondWithAnError(w, http.StatusInternalServerError, "couldn't decode request", err)
-		return err
-	}
-
-	multiplier, err := strconv.Atoi(r.FormValue("multiplier"))
-
-	if err != nil {
-		RespondWithAnError(w, http.StatusInternalServerError, "couldn't convert multiplier", err)
-		return err
-	}
-	_, err = fmt.Fprintf(
+func RespondWithNewPiece(w io.Writer, square components.Square, multiplier int) error {
+	_, err := fmt.Fprintf(
 		w,
 		GetSinglePieceMessage(),
 		square.Piece.Name,
@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/challenges"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
 	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -39,39 +49,80 @@ func main() {
 	startingBoard := matches.MakeBoard()
 	startingPieces := matches.MakePieces()
 
-	matches := matches.Matches{
-		Matches: map[string]matches.Match{
-			"initial": {
-				Board:                startingBoard,
-				Pieces:               startingPieces,
-				SelectedPiece:        components.Piece{},
-				CoordinateMultiplier: 80,
-				IsWhiteTurn:          true,
-				IsWhiteUnderCheck:    false,
-				IsBlackUnderCheck:    false,
-				WhiteTimer:           600,
-				BlackTimer:           600,
-				Addition:             0,
-				AllMoves:             []string{},
-			},
-		},
+	initial := matches.Match{
+		Board:                startingBoard,
+		Pieces:               startingPieces,
+		SelectedPiece:        components.Piece{},
+		CoordinateMultiplier: 80,
+		IsWhiteTurn:          true,
+		IsWhiteUnderCheck:    false,
+		IsBlackUnderCheck:    false,
+		WhiteTimer:           600,
+		BlackTimer:           600,
+		TurnStartTimer:       600,
+		Addition:             0,
+		AllMoves:             []string{},
 	}
+	initial.UpdateCoordinates(initial.CoordinateMultiplier)
+
+	challengeTTL, err := time.ParseDuration(os.Getenv("CHALLENGE_TTL"))
+	if err != nil || challengeTTL <= 0 {
+		challengeTTL = 10 * time.Minute
+	}
+
+	firstMoveTimeout, err := time.ParseDuration(os.Getenv("FIRST_MOVE_TIMEOUT"))
+	if err != nil || firstMoveTimeout <= 0 {
+		firstMoveTimeout = 30 * time.Second
+	}
+
+	shutdownTimeout, err := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if err != nil || shutdownTimeout <= 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+
+	allMatches := matches.NewMatches()
+	allMatches.SetMatch("initial", initial)
 
 	cfg := appConfig{
-		database: dbQueries,
-		secret:   secret,
-		users:    make(map[uuid.UUID]User, 0),
-		Matches:  matches,
+		db:               db,
+		database:         dbQueries,
+		secret:           secret,
+		users:            make(map[uuid.UUID]User, 0),
+		Matches:          allMatches,
+		Rematches:        matches.NewRematches(),
+		Challenges:       challenges.NewStore(challengeTTL),
+		Seeks:            queue.NewSeekPool(),
+		Seekers:          hub.New(),
+		chatFilter:       chat.NewWordFilter(strings.Split(os.Getenv("CHAT_BLOCKED_WORDS"), ",")...),
+		firstMoveTimeout: firstMoveTimeout,
 	}
 
-	cur, _ := cfg.Matches.GetMatch("initial")
-	cur.UpdateCoordinates(cur.CoordinateMultiplier)
+	cfg.Matches.OnEnd(cfg.matchEnded)
+	cfg.Matches.OnMove(cfg.matchMoved)
+	cfg.Seekers.OnConnect(cfg.seekerJoined)
+	cfg.Seekers.OnDisconnect(cfg.seekerLeft)
+	go cfg.runMatchmaker(time.Second)
+
+	err = cfg.restoreMatches(context.Background())
+	if err != nil {
+		responses.LogError("couldn't restore the games in progress", err)
+	}
 
 	http.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir("assets"))))
 	cfg.registerAllHandlers()
 
-	err = http.ListenAndServe(fmt.Sprintf(":%v", port), nil)
-	if err != nil {
-		responses.LogError("couldn't start the server", err)
-	}
+	server := &http.Server{Addr: fmt.Sprintf(":%v", port)}
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			responses.LogError("couldn't start the server", err)
+			cancel()
+		}
+	}()
+
+	<-stop.Done()
+	cfg.shutdown(server, shutdownTimeout)
 }
@@ -1,16 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/NikolaTosic-sudo/chess-live/containers/components"
 	layout "github.com/NikolaTosic-sudo/chess-live/containers/layouts"
 	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
 	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
 	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
 	"github.com/google/uuid"
@@ -22,279 +23,262 @@ var upgrader = websocket.Upgrader{
 }
 
 func (cfg *appConfig) wsHandler(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	currentGame := matches.GameKey(r.PathValue("id"))
+
+	userId, err := cfg.getUserId(r)
+
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "websocket upgrade failed", err)
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
 		return
 	}
-	c, err := r.Cookie("current_game")
-	if err != nil {
+
+	var game matches.OnlineGame
+	var isPlayer bool
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		game = match.Online
+		isPlayer = match.IsPlayer(userId)
+	})
+
+	if !ok || game.Hub == nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
 		return
 	}
 
-	userId, err := cfg.getUserId(r)
+	if !isPlayer {
+		responses.RespondWithAnError(w, http.StatusForbidden, "not a player in this game", fmt.Errorf("user %v isn't playing %v", userId, currentGame))
+		return
+	}
 
+	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		responses.LogError("websocket upgrade failed", err)
 		return
 	}
 
-	game := cfg.Matches.Matches[c.Value].Online
-	for color, player := range game.Players {
-		if player.ID == userId {
-			player.Conn = conn
-			game.Players[color] = player
-		}
-	}
-	disconnect := make(chan string)
-
-	go func() {
-		for {
-			select {
-			case msg := <-game.Message:
-				for playerColor, onlinePlayer := range game.Players {
-					err := onlinePlayer.Conn.WriteMessage(websocket.TextMessage, []byte(msg))
-					if err != nil {
-						responses.RespondWithAnError(w, http.StatusInternalServerError, fmt.Sprintf("WebSocket write error to: %v", playerColor), err)
-						break
-					}
-				}
-			case playerMsg := <-game.PlayerMsg:
-				player := <-game.Player
-				err := player.Conn.WriteMessage(websocket.TextMessage, []byte(playerMsg))
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, fmt.Sprintf("WebSocket write error to: %v", playerMsg), err)
-					continue
-				}
+	game.Hub.Serve(userId, conn)
+}
+
+func (cfg *appConfig) playerConnected(currentGame string) func(uuid.UUID) {
+	return func(userId uuid.UUID) {
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			if !match.IsPlayer(userId) {
+				return
 			}
-		}
-	}()
 
-	go func() {
+			opponent, hasOpponent := match.Opponent(userId)
 
-		<-disconnect
+			if match.MarkReconnected(userId) && hasOpponent {
+				match.Online.Hub.Send(opponent.ID, `<div id="wait" hx-swap-oob="outerHTML"></div>`)
+			}
 
-		err := conn.Close()
+			if offer := match.DrawOffer; offer.By != "" {
+				msg, err := utils.TemplString(components.DrawOfferBox(offer.By, match.Online.Players[offer.By].ID != userId))
+				if err != nil {
+					responses.LogError("couldn't render draw offer", err)
+				} else {
+					match.Online.Hub.Send(userId, msg)
+				}
+			}
 
-		if err != nil {
-			log.Println(err, "connection closed")
-			return
-		}
+			toMove := match.Online.Players["black"]
+			if match.IsWhiteTurn {
+				toMove = match.Online.Players["white"]
+			}
+			if toMove.ID == userId {
+				msg, err := utils.TemplString(components.ClaimDrawButton(match.ClaimableDraw()))
+				if err != nil {
+					responses.LogError("couldn't render claim draw button", err)
+				} else {
+					match.Online.Hub.Send(userId, msg)
+				}
+			}
 
-		for _, connect := range game.Players {
-			if conn != connect.Conn {
-				msg, err := utils.TemplString(components.WaitForReconnectModal())
+			if tiles := match.PremoveTiles(userId); len(tiles) > 0 {
+				msg, err := premovesMessage(match, userId)
 				if err != nil {
-					log.Println(err, "error")
-					return
+					responses.LogError("couldn't render premoves", err)
+				} else {
+					match.Online.Hub.Send(userId, msg)
 				}
+			}
 
-				err = connect.Conn.WriteMessage(websocket.TextMessage, []byte(msg))
+			if hasOpponent && match.Online.TakebackBy == opponent.ID {
+				msg, err := utils.TemplString(components.TakebackOfferedModal())
 				if err != nil {
-					log.Println(err, "error")
-					return
+					responses.LogError("couldn't render takeback modal", err)
+				} else {
+					match.Online.Hub.Send(userId, msg)
 				}
 			}
+		})
+	}
+}
 
+func (cfg *appConfig) playerDisconnected(currentGame string) func(uuid.UUID) {
+	return func(userId uuid.UUID) {
+		msg, err := utils.TemplString(components.WaitForReconnectModal(int(matches.ReconnectGracePeriod / time.Second)))
+		if err != nil {
+			responses.LogError("couldn't render reconnect modal", err)
+			return
 		}
 
-	}()
-
-	go func() {
-		for {
-			_, _, err := conn.ReadMessage()
-			if e, ok := err.(*websocket.CloseError); ok && e.Code == websocket.CloseNormalClosure {
-				log.Println(err, "normal close")
-				break
-			}
-			if strings.Contains(err.Error(), "websocket: RSV1 set, bad opcode 7, bad MASK") {
-				disconnect <- "disconnected"
+		var isPlayer bool
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			isPlayer = match.IsPlayer(userId)
+			if !isPlayer {
+				return
 			}
-			if err != nil {
-				break
+
+			match.MarkDisconnected(userId, time.Now())
+
+			if opponent, found := match.Opponent(userId); found {
+				match.Online.Hub.Send(opponent.ID, msg)
 			}
+		})
+
+		if !isPlayer {
+			return
 		}
-	}()
-}
 
-func (cfg *appConfig) searchingOppHandler(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
-		return
+		time.AfterFunc(matches.ReconnectGracePeriod, func() {
+			cfg.adjudicateAbandonment(currentGame, userId, false)
+		})
 	}
-	currentGame := c.Value
+}
 
-	game := cfg.Matches.Matches[currentGame].Online
-	var emptyPlayer components.OnlinePlayerStruct
-	if game.Players["black"] == emptyPlayer {
-		w.WriteHeader(http.StatusNoContent)
-		return
-	}
+// adjudicateAbandonment ends the game against a player whose reconnect grace
+// period ran out, or who chose not to come back when left is true.
+func (cfg *appConfig) adjudicateAbandonment(currentGame string, userId uuid.UUID, left bool) {
+	var finished matches.Match
+	adjudicated := false
 
-	whitePlayer := game.Players["white"]
-	blackPlayer := game.Players["black"]
-	startGame := cfg.makeCookie("current_game", currentGame, "/")
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if !left && !match.ReconnectGraceExpired(userId, time.Now()) {
+			return
+		}
 
-	match, _ := cfg.Matches.GetMatch(currentGame)
-	match.FillBoard()
-	match.UpdateCoordinates(whitePlayer.Multiplier)
-	http.SetCookie(w, &startGame)
+		gameResult, winner, aborted, err := match.AbandonmentResult(userId)
+		if err != nil {
+			return
+		}
+
+		var msg string
+		var termination string
+		if aborted {
+			termination = matches.TerminationAborted
+			msg, err = utils.TemplString(components.GameAborted())
+		} else {
+			termination = matches.TerminationAbandoned
+			msg, err = utils.TemplString(components.EndGameModal(gameResult, winner, false))
+			msg = `<div id="wait" hx-swap-oob="outerHTML"></div>` + msg
+		}
+		if err != nil {
+			responses.LogError("couldn't render end game modal", err)
+		} else {
+			match.Online.Hub.Broadcast(msg)
+		}
+		match.PublishGameEnd(gameResult, termination)
 
-	_ = cfg.database.CreateMatchUser(r.Context(), database.CreateMatchUserParams{
-		UserID:  whitePlayer.ID,
-		MatchID: match.MatchId,
+		finished = match.Clone()
+		adjudicated = true
 	})
 
-	err = layout.MainPageOnline(match.Board, match.Pieces, whitePlayer.Multiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, true).Render(r.Context(), w)
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	if !adjudicated {
 		return
 	}
+
+	cfg.finishOnlineMatch(currentGame, finished)
+}
+
+// finishOnlineMatch drops a finished online match and disconnects everyone
+// still following it. Its result was already stored when it ended.
+func (cfg *appConfig) finishOnlineMatch(currentGame string, finished matches.Match) {
+	cfg.Matches.DeleteMatch(currentGame)
+	finished.Online.Close()
 }
 
 func (cfg *appConfig) waitingForReconnect(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
+	currentGame, err := cfg.currentGame(r)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusNotFound, "current game unavailable", err)
 		return
 	}
-	userC, err := r.Cookie("access_token")
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "access token unavailable", err)
-		return
-	}
-	userId, err := auth.ValidateJWT(userC.Value, cfg.secret)
+	userId, err := cfg.getUserId(r)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "couldn't validate jwt", err)
+		responses.RespondWithAnError(w, http.StatusNotFound, "user not found", err)
 		return
 	}
-	game := cfg.Matches.Matches[c.Value].Online
-
-	err1 := game.Players["white"].Conn.WriteMessage(websocket.TextMessage, []byte("test"))
-	err2 := game.Players["black"].Conn.WriteMessage(websocket.TextMessage, []byte("test"))
-
-	if err1 == nil && err2 == nil {
-
-		_, err = fmt.Fprintf(w, `<div id="wait" hx-swap-oob="outerHTML"></div>`)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
-			return
+	var secondsLeft int
+	var waiting bool
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if opponent, found := match.Opponent(userId); found {
+			secondsLeft, waiting = match.ReconnectSecondsLeft(opponent.ID, time.Now())
 		}
+	})
 
-		return
-	}
-
-	var time int8
-	var result string
-	var winner string
-	if userId == game.Players["white"].ID {
-		gamePlayer := game.Players["black"]
-		time = gamePlayer.ReconnectTimer
-		time -= 1
-		gamePlayer.ReconnectTimer = time
-		game.Players["black"] = gamePlayer
-		result = "1-0"
-		winner = "white"
-	} else {
-		gamePlayer := game.Players["white"]
-		time = gamePlayer.ReconnectTimer
-		time -= 1
-		gamePlayer.ReconnectTimer = time
-		game.Players["white"] = gamePlayer
-		result = "0-1"
-		winner = "black"
-	}
-	if time < 0 {
+	if !waiting {
 		_, err = fmt.Fprintf(w, `<div id="wait" hx-swap-oob="outerHTML"></div>`)
 		if err != nil {
 			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 			return
 		}
 
-		err := components.EndGameModal(result, winner, false).Render(r.Context(), w)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
-			return
-		}
 		return
 	}
 
-	_, err = fmt.Fprintf(w, `<span id="waiting" hx-swap-oob="true">%v</span>`, time)
+	_, err = fmt.Fprintf(w, `<span id="waiting" hx-swap-oob="true">%v</span>`, secondsLeft)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't send time", err)
 		return
 	}
 }
 
-func (cfg *appConfig) checkOnlineHandler(w http.ResponseWriter, r *http.Request) {
-	c, err := r.Cookie("current_game")
-	if err != nil {
-		return
-	}
-
-	match, found := cfg.Matches.GetMatch(c.Value)
-
-	if found {
-		onlineGame, foundOnline := match.IsOnlineMatch()
-
-		if foundOnline && onlineGame.Players["white"].ID != uuid.Nil {
-			err := components.ReconnectModal().Render(r.Context(), w)
-			if err != nil {
-				responses.RespondWithAnError(w, http.StatusInternalServerError, "Couldn't render template", err)
-				return
-			}
-		} else {
-			rmCk := cfg.removeCookie("current_game")
-			delete(cfg.Matches.Matches, c.Value)
-			http.SetCookie(w, &rmCk)
+// ongoingOnlineGame finds an online game the player is still seated in.
+func (cfg *appConfig) ongoingOnlineGame(userId uuid.UUID) (string, bool) {
+	for _, currentGame := range cfg.Matches.GetAllOnlineMatches() {
+		match, ok := cfg.Matches.GetMatch(currentGame)
+		if ok && match.Result == "" && match.IsPlayer(userId) {
+			return currentGame, true
 		}
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return "", false
 }
 
-func (cfg *appConfig) cancelOnlineHandler(w http.ResponseWriter, r *http.Request) {
-	currentGame, err := r.Cookie("current_game")
+func (cfg *appConfig) checkOnlineHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	userToken, err := r.Cookie("access_token")
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "user not found", err)
+	currentGame, found := cfg.ongoingOnlineGame(userId)
+	if !found {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	userId, err := auth.ValidateJWT(userToken.Value, cfg.secret)
+	err = components.ReconnectModal(currentGame, matches.GamePath(currentGame)).Render(r.Context(), w)
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "invalid token", err)
-		return
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "Couldn't render template", err)
 	}
+}
 
-	saveGame, _ := cfg.Matches.GetMatch(currentGame.Value)
-
-	onlineGame := cfg.Matches.Matches[currentGame.Value].Online
-
-	var result string
-	if onlineGame.Players["white"].ID == userId {
-		result = "0-1"
-	} else {
-		result = "1-0"
+func (cfg *appConfig) cancelOnlineHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "user not found", err)
+		return
 	}
 
-	err = cfg.database.UpdateMatchOnEnd(r.Context(), database.UpdateMatchOnEndParams{
-		Result: result,
-		ID:     saveGame.MatchId,
-	})
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "error updating match", err)
+	currentGame := r.URL.Query().Get("game")
+	match, found := cfg.Matches.GetMatch(currentGame)
+	if !found || !match.IsPlayer(userId) {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
 		return
 	}
 
-	cGC := cfg.removeCookie("current_game")
-	http.SetCookie(w, &cGC)
+	cfg.adjudicateAbandonment(currentGame, userId, true)
 
 	_, err = fmt.Fprintf(w, `<div id="rec" hx-swap-oob="outerHTML"></div>`)
 
@@ -304,92 +288,111 @@ func (cfg *appConfig) cancelOnlineHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
-func (cfg *appConfig) continueOnlineHandler(w http.ResponseWriter, r *http.Request) {
-	currentGame, err := r.Cookie("current_game")
+// startOnlineMatch creates an online match between two known players and
+// seats both of them in it straight away.
+func (cfg *appConfig) startOnlineMatch(white, black components.OnlinePlayerStruct, fullTime, addition int, rated bool) (string, error) {
+	randomString, err := auth.MakeRefreshToken()
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
-		return
-	}
-
-	userToken, err := r.Cookie("access_token")
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "user not found", err)
-		return
-	}
-
-	userId, err := auth.ValidateJWT(userToken.Value, cfg.secret)
+		return "", err
+	}
+	newGame := fmt.Sprintf("online:%v", randomString)
+
+	whitePlayer := white
+	whitePlayer.Pieces = "white"
+	whitePlayer.Timer = utils.FormatTime(fullTime)
+	blackPlayer := black
+	blackPlayer.Pieces = "black"
+	blackPlayer.Timer = utils.FormatTime(fullTime)
+
+	matchId, err := cfg.database.CreateMatch(context.Background(), database.CreateMatchParams{
+		White:    whitePlayer.Name,
+		Black:    blackPlayer.Name,
+		FullTime: int32(fullTime),
+		IsOnline: true,
+		Addition: int32(addition),
+		Variant:  queue.VariantStandard,
+		Rated:    rated,
+		StartFen: matches.StartFEN,
+		WhiteID:  uuid.NullUUID{UUID: whitePlayer.ID, Valid: true},
+		BlackID:  uuid.NullUUID{UUID: blackPlayer.ID, Valid: true},
+	})
 	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "invalid token", err)
-		return
+		return "", err
 	}
 
-	user, err := cfg.database.GetUserById(r.Context(), userId)
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusNotFound, "user not found", err)
-		return
+	for _, id := range []uuid.UUID{whitePlayer.ID, blackPlayer.ID} {
+		err = cfg.database.CreateMatchUser(context.Background(), database.CreateMatchUserParams{
+			UserID:  id,
+			MatchID: matchId,
+		})
+		if err != nil {
+			return "", err
+		}
 	}
 
-	match, ok := cfg.Matches.GetMatch(currentGame.Value)
-
-	onlineGame, ok2 := matches.OnlineGame{}, false
-
-	if ok {
-		onlineGame, ok2 = match.IsOnlineMatch()
+	online := cfg.newOnlineGame(newGame)
+	online.Players["white"] = whitePlayer
+	online.Players["black"] = blackPlayer
+
+	match := matches.Match{
+		Board:                matches.MakeBoard(),
+		Pieces:               matches.MakePieces(),
+		CoordinateMultiplier: whitePlayer.Multiplier,
+		IsWhiteTurn:          true,
+		WhiteTimer:           fullTime,
+		BlackTimer:           fullTime,
+		TurnStartTimer:       fullTime,
+		TurnStarted:          time.Now(),
+		FullTime:             fullTime,
+		Addition:             addition,
+		Rated:                rated,
+		MatchId:              matchId,
+		IsOnline:             true,
+		Online:               online,
 	}
+	match.FillBoard()
+	match.UpdateCoordinates(whitePlayer.Multiplier)
 
-	if !ok || !ok2 {
-
-		cGC := cfg.removeCookie("current_game")
-		http.SetCookie(w, &cGC)
-
-		match, _ := cfg.Matches.GetMatch("initial")
-		match.FillBoard()
-
-		whitePlayer := components.PlayerStruct{
-			Image:  "/assets/images/user-icon.png",
-			Name:   user.Name,
-			Timer:  utils.FormatTime(match.WhiteTimer),
-			Pieces: "white",
-		}
-		blackPlayer := components.PlayerStruct{
-			Image:  "/assets/images/user-icon.png",
-			Name:   "Opponent",
-			Timer:  utils.FormatTime(match.BlackTimer),
-			Pieces: "black",
-		}
-
-		err = layout.MainPagePrivate(match.Board, match.Pieces, match.CoordinateMultiplier, whitePlayer, blackPlayer, match.TakenPiecesWhite, match.TakenPiecesBlack, true).Render(r.Context(), w)
+	cfg.Matches.SetMatch(newGame, match)
+	cfg.watchFirstMove(newGame)
 
-		if err != nil {
-			responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
-			return
-		}
+	return newGame, nil
+}
 
+// joinOnlineMatch moves the player onto the board of a match started with
+// startOnlineMatch. Only one of the two pages polls the timer, like in any
+// other online game.
+func (cfg *appConfig) joinOnlineMatch(w http.ResponseWriter, r *http.Request, newGame string, userId uuid.UUID, enabled bool) {
+	match, ok := cfg.Matches.GetMatch(newGame)
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", newGame))
 		return
 	}
 
-	var blackPlayer components.OnlinePlayerStruct
-	var whitePlayer components.OnlinePlayerStruct
-
-	for color, player := range onlineGame.Players {
-		if color == "white" {
-			whitePlayer = player
-		}
+	w.Header().Set("HX-Retarget", "#body")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	w.Header().Set("HX-Push-Url", matches.GamePath(newGame))
 
-		if color == "black" {
-			blackPlayer = player
-		}
+	err := cfg.renderOnlineGame(w, r, newGame, match, userId, enabled)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 	}
+}
 
-	var multiplier int
+// renderOnlineGame renders the page of an online game for one of its players,
+// with the board the way they see it.
+func (cfg *appConfig) renderOnlineGame(w http.ResponseWriter, r *http.Request, currentGame string, match matches.Match, userId uuid.UUID, enabled bool) error {
+	whitePlayer := match.Online.Players["white"]
+	blackPlayer := match.Online.Players["black"]
+	whitePlayer.Timer = utils.FormatTime(match.WhiteTimer)
+	blackPlayer.Timer = utils.FormatTime(match.BlackTimer)
 
+	multiplier := whitePlayer.Multiplier
 	if blackPlayer.ID == userId {
 		multiplier = blackPlayer.Multiplier
-	} else {
-		multiplier = whitePlayer.Multiplier
 	}
 
-	err = layout.MainPageOnline(
+	return layout.MainPageOnline(
 		match.Board,
 		match.Pieces,
 		multiplier,
@@ -397,104 +400,11 @@ func (cfg *appConfig) continueOnlineHandler(w http.ResponseWriter, r *http.Reque
 		blackPlayer,
 		match.TakenPiecesWhite,
 		match.TakenPiecesBlack,
-		true,
+		match.MoveState(),
+		enabled,
+		match.Abortable(),
+		matches.GamePath(currentGame),
+		matches.WatchPath(currentGame),
+		match.SpectatorCount(),
 	).Render(r.Context(), w)
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "websocket upgrade failed", err)
-		return
-	}
-}
-
-func (cfg *appConfig) cancelOnlineSearchHandler(w http.ResponseWriter, r *http.Request) {
-
-	currentGame, err := r.Cookie("current_game")
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "failed getting the cookie", err)
-		return
-	}
-
-	cGC := cfg.removeCookie("current_game")
-	http.SetCookie(w, &cGC)
-
-	delete(cfg.Matches.Matches, currentGame.Value)
-
-	_, err = w.Write([]byte{})
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "failed closing the modal", err)
-		return
-	}
-}
-
-func (cfg *appConfig) declineDrawHandler(w http.ResponseWriter, r *http.Request) {
-	currentGame, err := r.Cookie("current_game")
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "failed getting the cookie", err)
-		return
-	}
-
-	userId, err := cfg.getUserId(r)
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "failed getting user", err)
-		return
-	}
-
-	match, _ := cfg.Matches.GetMatch(currentGame.Value)
-
-	onlineGame, found := match.IsOnlineMatch()
-
-	if found {
-		for _, player := range onlineGame.Players {
-			if userId == player.ID {
-				_, err = fmt.Fprintf(w, `<div id="rec" hx-swap-oob="outerHTML"></div>`)
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "failed sending message", err)
-					return
-				}
-			} else {
-				msg := `
-					<div id="timer-update" hx-get="/timer" hx-trigger="every 1s" hx-swap-oob="true"></div>
-					<div id="wait" hx-swap-oob="outerHTML"></div>
-				`
-				onlineGame.PlayerMsg <- msg
-				onlineGame.Player <- player
-				if err != nil {
-					responses.RespondWithAnError(w, http.StatusInternalServerError, "failed sending message", err)
-					return
-				}
-			}
-		}
-	}
-}
-
-func (cfg *appConfig) accpetDrawHandler(w http.ResponseWriter, r *http.Request) {
-	currentGame, err := r.Cookie("current_game")
-
-	if err != nil {
-		responses.RespondWithAnError(w, http.StatusInternalServerError, "failed getting the cookie", err)
-		return
-	}
-
-	match, _ := cfg.Matches.GetMatch(currentGame.Value)
-
-	onlineGame, found := match.IsOnlineMatch()
-
-	if found {
-		msg, err := utils.TemplString(components.EndGameModal("1-1", "", true))
-
-		message := fmt.Sprintf(`
-			<div id="rec" hx-swap-oob="outerHTML"></div>
-			<div id="wait" hx-swap-oob="outerHTML"></div>
-			%v
-		`, msg)
-		if err != nil {
-			responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting to string", err)
-			return
-		}
-
-		onlineGame.Message <- message
-	}
 }
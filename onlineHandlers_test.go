@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+func newTestOnlineMatch(t *testing.T, cfg *appConfig, currentGame string, white, black uuid.UUID) {
+	t.Helper()
+
+	matchHub := hub.New()
+	matchHub.OnConnect(cfg.playerConnected(currentGame))
+	matchHub.OnDisconnect(cfg.playerDisconnected(currentGame))
+	t.Cleanup(matchHub.Close)
+
+	cfg.Matches.SetMatch(currentGame, matches.Match{
+		IsOnline: true,
+		Online: matches.OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: white, Pieces: "white"},
+				"black": {ID: black, Pieces: "black"},
+			},
+			Hub: matchHub,
+		},
+	})
+}
+
+func TestWsHandler(t *testing.T) {
+	cfg := &appConfig{
+		secret:  "test-secret",
+		Matches: matches.NewMatches(),
+	}
+
+	white := uuid.New()
+	black := uuid.New()
+	currentGame := "online:abc123"
+	newTestOnlineMatch(t, cfg, currentGame, white, black)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /game/{id}/ws", cfg.wsHandler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	token := func(id uuid.UUID) string {
+		token, err := auth.MakeJWT(id, cfg.secret)
+		if err != nil {
+			t.Fatalf("MakeJWT() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{
+			name:       "Player",
+			path:       matches.GamePath(currentGame),
+			token:      token(white),
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "Someone who isn't playing",
+			path:       matches.GamePath(currentGame),
+			token:      token(uuid.New()),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Unknown game",
+			path:       "/game/missing",
+			token:      token(white),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "No token",
+			path:       matches.GamePath(currentGame),
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.token != "" {
+				header.Set("Cookie", "access_token="+tt.token)
+			}
+
+			url := "ws" + strings.TrimPrefix(server.URL, "http") + tt.path + "/ws"
+			conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+			if conn != nil {
+				_ = conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("Dial() error = %v, no response", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Dial() status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPlayerDisconnectedIgnoresOutsiders(t *testing.T) {
+	cfg := &appConfig{Matches: matches.NewMatches()}
+
+	white := uuid.New()
+	black := uuid.New()
+	currentGame := "online:abc123"
+	newTestOnlineMatch(t, cfg, currentGame, white, black)
+
+	outsider := uuid.New()
+	cfg.playerConnected(currentGame)(outsider)
+	cfg.playerDisconnected(currentGame)(outsider)
+	cfg.adjudicateAbandonment(currentGame, outsider, true)
+
+	match, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok {
+		t.Fatalf("match %v was dropped by an outsider leaving", currentGame)
+	}
+	if len(match.Online.Disconnected) != 0 {
+		t.Errorf("Disconnected = %v, want nobody", match.Online.Disconnected)
+	}
+}
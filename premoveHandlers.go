@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// premovesMessage highlights the player's premoves on their own board.
+func premovesMessage(match *matches.Match, player uuid.UUID) (string, error) {
+	multiplier := 1
+	for _, p := range match.Online.Players {
+		if p.ID == player {
+			multiplier = max(p.Multiplier, 1)
+		}
+	}
+
+	var squares [][2]int
+	for _, tile := range match.PremoveTiles(player) {
+		position := match.Board[tile].CoordinatePosition
+		squares = append(squares, [2]int{position[0] * multiplier, position[1] * multiplier})
+	}
+
+	return utils.TemplString(components.Premoves(squares))
+}
+
+// premoveClick handles a click on the board by the player who isn't on move.
+// The board itself stays as it is, only the highlights change.
+func premoveClick(w http.ResponseWriter, match *matches.Match, player uuid.UUID, tile string) {
+	w.Header().Set("HX-Reswap", "none")
+
+	err := match.SelectPremove(player, tile)
+	if errors.Is(err, matches.ErrNotAPlayer) || errors.Is(err, matches.ErrMatchOver) {
+		responses.RespondWithAnError(w, http.StatusForbidden, "premove not allowed", err)
+		return
+	}
+
+	msg, err := premovesMessage(match, player)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render premoves", err)
+		return
+	}
+
+	_, err = fmt.Fprint(w, msg)
+	if err != nil {
+		responses.LogError("couldn't write to page", err)
+	}
+}
+
+func (cfg *appConfig) cancelPremovesHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		match.CancelPremoves(userId)
+
+		msg, err := premovesMessage(match, userId)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render premoves", err)
+			return
+		}
+
+		_, err = fmt.Fprint(w, msg)
+		if err != nil {
+			responses.LogError("couldn't write to page", err)
+		}
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+	}
+}
+
+// playPremoves plays the premoves of the player on move right after the
+// opponent's move, in the same command that played it, so nothing else can get
+// to the match in between. A premove that turns out illegal drops the rest of
+// the chain with it.
+func (cfg *appConfig) playPremoves(ctx context.Context, match *matches.Match, currentGame string) {
+	for match.IsOnline {
+		player, premove, found := match.NextPremove()
+		if !found {
+			// A piece picked for a premove that never got queued is just
+			// forgotten once it's the player's turn.
+			if len(match.PremoveTiles(player)) > 0 {
+				match.CancelPremoves(player)
+				sendPremoves(match, player)
+			}
+			return
+		}
+
+		err := cfg.playUci(ctx, match, currentGame, player, premove.UCI())
+		if err != nil {
+			match.CancelPremoves(player)
+		}
+		sendPremoves(match, player)
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// sendPremoves updates the premove highlights on the player's board.
+func sendPremoves(match *matches.Match, player uuid.UUID) {
+	msg, err := premovesMessage(match, player)
+	if err != nil {
+		responses.LogError("couldn't render premoves", err)
+		return
+	}
+	match.Online.Hub.Send(player, msg)
+}
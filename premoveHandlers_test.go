@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/google/uuid"
+)
+
+func TestPremoveIsPlayedWithTheOpponentsMove(t *testing.T) {
+	cfg := &appConfig{Matches: matches.NewMatches()}
+
+	white := uuid.New()
+	black := uuid.New()
+	currentGame := "online:abc123"
+
+	matchHub := hub.New()
+	t.Cleanup(matchHub.Close)
+
+	match := matches.Match{
+		Board:       matches.MakeBoard(),
+		Pieces:      matches.MakePieces(),
+		IsWhiteTurn: true,
+		WhiteTimer:  600,
+		BlackTimer:  600,
+		FullTime:    600,
+		IsOnline:    true,
+		Online: matches.OnlineGame{
+			Players: map[string]components.OnlinePlayerStruct{
+				"white": {ID: white, Pieces: "white", Multiplier: 1},
+				"black": {ID: black, Pieces: "black", Multiplier: 1},
+			},
+			Hub: matchHub,
+		},
+	}
+	match.FillBoard()
+	match.UpdateCoordinates(1)
+	cfg.Matches.SetMatch(currentGame, match)
+
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		for _, tile := range []string{"7e", "5e"} {
+			if err := match.SelectPremove(black, tile); err != nil {
+				t.Fatalf("SelectPremove(%v) error = %v", tile, err)
+			}
+		}
+	})
+
+	// Every command that gets to the match sees either no move or both of
+	// them, never white's move waiting for the premove.
+	stop := make(chan struct{})
+	var seen []int
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			cfg.Matches.Do(currentGame, func(match *matches.Match) {
+				seen = append(seen, len(match.UciMoves))
+			})
+		}
+	}()
+
+	err := cfg.playFeedMove(currentGame, white, "e2e4")
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("playFeedMove() error = %v", err)
+	}
+
+	if slices.Contains(seen, 1) {
+		t.Errorf("a command ran between white's move and black's premove")
+	}
+
+	got, _ := cfg.Matches.GetMatch(currentGame)
+	if want := []string{"e2e4", "e7e5"}; !slices.Equal(got.UciMoves, want) {
+		t.Errorf("moves = %v, want %v", got.UciMoves, want)
+	}
+	if !got.IsWhiteTurn {
+		t.Errorf("black is still on move after the premove")
+	}
+}
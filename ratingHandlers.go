@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/ratings"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/google/uuid"
+)
+
+// recordMatchEnd stores the result of the match and, for rated online games,
+// the new ratings of both players in one transaction. Only the first call for
+// a match counts, however many pages report the same ending.
+func (cfg *appConfig) recordMatchEnd(ctx context.Context, match matches.Match, result, termination string) error {
+	if match.MatchId == 0 {
+		return nil
+	}
+
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := cfg.database.WithTx(tx)
+
+	ended, err := q.IsMatchEndedForUpdate(ctx, match.MatchId)
+	if err != nil {
+		return err
+	}
+	if ended {
+		return nil
+	}
+
+	err = q.UpdateMatchOnEnd(ctx, database.UpdateMatchOnEndParams{
+		Result:      result,
+		Termination: termination,
+		ID:          match.MatchId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if match.IsOnline && match.Rated {
+		err = updateRatings(ctx, q, match, result)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// matchEnded stores the result of every match the moment the server decides
+// it, whether or not the players' pages ever report back, and drops its
+// checkpoint since there's nothing left to pick up after a restart.
+func (cfg *appConfig) matchEnded(currentGame string, match matches.Match) {
+	err := cfg.recordMatchEnd(context.Background(), match, match.Result, match.Termination)
+	if err != nil {
+		responses.LogError("couldn't record the end of the match", err)
+	}
+
+	if matches.Durable(currentGame) {
+		err = cfg.database.DeleteLiveMatch(context.Background(), currentGame)
+		if err != nil {
+			responses.LogError("couldn't drop the checkpoint", err)
+		}
+	}
+}
+
+func updateRatings(ctx context.Context, q *database.Queries, match matches.Match, result string) error {
+	whiteScore, blackScore, ok := ratings.Scores(result)
+	if !ok {
+		return nil
+	}
+
+	category := ratings.Category(match.FullTime, match.Addition)
+	white := match.Online.Players["white"]
+	black := match.Online.Players["black"]
+
+	// Both rows stay locked until the transaction ends, so another game of
+	// the same player can't read the rating this one is about to replace.
+	// They're always locked in the same order, so two games of the same
+	// players can't wait on each other.
+	ids := []uuid.UUID{white.ID, black.ID}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	locked := make(map[uuid.UUID]ratings.Rating, len(ids))
+	for _, id := range ids {
+		rating, err := lockRating(ctx, q, id, category)
+		if err != nil {
+			return err
+		}
+		locked[id] = rating
+	}
+	whiteRating, blackRating := locked[white.ID], locked[black.ID]
+
+	updates := []struct {
+		id     uuid.UUID
+		before ratings.Rating
+		after  ratings.Rating
+	}{
+		{white.ID, whiteRating, ratings.Update(whiteRating, []ratings.Result{{Opponent: blackRating, Score: whiteScore}})},
+		{black.ID, blackRating, ratings.Update(blackRating, []ratings.Result{{Opponent: whiteRating, Score: blackScore}})},
+	}
+
+	for _, update := range updates {
+		err := q.UpsertRating(ctx, database.UpsertRatingParams{
+			UserID:     update.id,
+			Category:   category,
+			Rating:     update.after.Rating,
+			Deviation:  update.after.Deviation,
+			Volatility: update.after.Volatility,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateRatingHistory(ctx, database.CreateRatingHistoryParams{
+			UserID:       update.id,
+			MatchID:      match.MatchId,
+			Category:     category,
+			RatingBefore: update.before.Rating,
+			RatingAfter:  update.after.Rating,
+			Deviation:    update.after.Deviation,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func currentRating(ctx context.Context, q *database.Queries, userId uuid.UUID, category string) (ratings.Rating, error) {
+	rating, err := q.GetRating(ctx, database.GetRatingParams{
+		UserID:   userId,
+		Category: category,
+	})
+	return toRating(rating, err)
+}
+
+// lockRating reads the rating like currentRating and locks its row until the
+// end of the transaction q runs in. A player's first rated game has no row to
+// lock yet, so the default one is inserted first.
+func lockRating(ctx context.Context, q *database.Queries, userId uuid.UUID, category string) (ratings.Rating, error) {
+	start := ratings.Default()
+	err := q.EnsureRating(ctx, database.EnsureRatingParams{
+		UserID:     userId,
+		Category:   category,
+		Rating:     start.Rating,
+		Deviation:  start.Deviation,
+		Volatility: start.Volatility,
+	})
+	if err != nil {
+		return ratings.Rating{}, err
+	}
+
+	rating, err := q.GetRatingForUpdate(ctx, database.GetRatingForUpdateParams{
+		UserID:   userId,
+		Category: category,
+	})
+	return toRating(rating, err)
+}
+
+func toRating(rating database.Rating, err error) (ratings.Rating, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ratings.Default(), nil
+	}
+	if err != nil {
+		return ratings.Rating{}, err
+	}
+
+	return ratings.Rating{
+		Rating:     rating.Rating,
+		Deviation:  rating.Deviation,
+		Volatility: rating.Volatility,
+	}, nil
+}
+
+// renderRatingChange fills the rating line of the end game modal, if the
+// match changed the user's rating.
+func (cfg *appConfig) renderRatingChange(w http.ResponseWriter, r *http.Request, matchId int32, userId uuid.UUID) {
+	change, err := cfg.database.GetRatingChangeForMatch(r.Context(), database.GetRatingChangeForMatchParams{
+		MatchID: matchId,
+		UserID:  userId,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			responses.LogError("couldn't get the rating change", err)
+		}
+		return
+	}
+
+	err = components.RatingChange(
+		int(math.Round(change.RatingAfter)),
+		int(math.Round(change.RatingAfter)-math.Round(change.RatingBefore)),
+		change.Deviation > ratings.ProvisionalDeviation,
+	).Render(r.Context(), w)
+	if err != nil {
+		responses.LogError("couldn't render the rating change", err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/google/uuid"
+)
+
+func (cfg *appConfig) rematchStatusHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	game := r.URL.Query().Get("game")
+
+	rematch, ok := cfg.Rematches.Get(game, time.Now())
+	if !ok || !rematch.IsPlayer(userId) {
+		_, err = fmt.Fprint(w, `<div id="rematch"></div>`)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+		}
+		return
+	}
+
+	if rematch.NewGame != "" {
+		cfg.joinOnlineMatch(w, r, rematch.NewGame, userId, false)
+		return
+	}
+
+	cfg.renderRematch(w, r, game, rematch, userId)
+}
+
+func (cfg *appConfig) rematchHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't decode request", err)
+		return
+	}
+	game := r.FormValue("game")
+
+	rematch, accepted, err := cfg.Rematches.Offer(game, userId, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	if !accepted {
+		cfg.renderRematch(w, r, game, rematch, userId)
+		return
+	}
+
+	newGame, err := cfg.startRematch(rematch)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't start the rematch", err)
+		return
+	}
+	cfg.Rematches.Started(game, newGame, time.Now())
+
+	cfg.joinOnlineMatch(w, r, newGame, userId, true)
+}
+
+func (cfg *appConfig) declineRematchHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't decode request", err)
+		return
+	}
+	game := r.FormValue("game")
+
+	err = cfg.Rematches.Decline(game, userId, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	err = components.RematchBox(game, false, false, true).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
+
+func (cfg *appConfig) renderRematch(w http.ResponseWriter, r *http.Request, game string, rematch matches.Rematch, userId uuid.UUID) {
+	offered := rematch.OfferedBy == userId
+	incoming := rematch.OfferedBy != uuid.Nil && !offered
+
+	err := components.RematchBox(game, offered, incoming, rematch.Declined).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
+
+// startRematch creates the new match with the colors swapped.
+func (cfg *appConfig) startRematch(rematch matches.Rematch) (string, error) {
+	return cfg.startOnlineMatch(rematch.Black, rematch.White, rematch.FullTime, rematch.Addition, rematch.Rated)
+}
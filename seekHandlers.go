@@ -0,0 +1,347 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	layout "github.com/NikolaTosic-sudo/chess-live/containers/layouts"
+	"github.com/NikolaTosic-sudo/chess-live/internal/challenges"
+	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
+	"github.com/NikolaTosic-sudo/chess-live/internal/ratings"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	errSeekGone    = errors.New("this seek is no longer open")
+	errGuestRated  = errors.New("log in to play rated games")
+	errOwnSeekPlay = errors.New("you can't play against your own seek")
+)
+
+// lobbyHandler shows every open seek. The page stays connected to get the
+// list as it changes and to be moved into the game once a seek is answered.
+// Visitors without an account get a guest identity here.
+func (cfg *appConfig) lobbyHandler(w http.ResponseWriter, r *http.Request) {
+	player, guest, err := cfg.getPlayer(w, r)
+	if err != nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Please try again")
+		return
+	}
+
+	err = layout.Lobby(seekStructs(cfg.Seeks.All(), player.ID), guest).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
+	}
+}
+
+// createSeekHandler opens a seek for the user. Guests' games are never rated.
+func (cfg *appConfig) createSeekHandler(w http.ResponseWriter, r *http.Request) {
+	player, guest, err := cfg.getPlayer(w, r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't get the player", err)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't decode request", err)
+		return
+	}
+
+	minutes, _ := strconv.Atoi(r.FormValue("minutes"))
+	addition, _ := strconv.Atoi(r.FormValue("addition"))
+	color := r.FormValue("color")
+
+	err = challenges.Validate(minutes, addition, color)
+	if err != nil {
+		cfg.lobbyError(w, err)
+		return
+	}
+
+	pool := queue.Pool{
+		FullTime: minutes * 60,
+		Addition: addition,
+		Variant:  queue.VariantStandard,
+		Rated:    !guest && r.FormValue("rated") != "",
+	}
+
+	rating := ratings.Default()
+	if !guest {
+		rating, err = currentRating(r.Context(), cfg.database, player.ID, ratings.Category(pool.FullTime, pool.Addition))
+		if err != nil {
+			responses.LogError("couldn't get the rating of the seeker", err)
+			rating = ratings.Default()
+		}
+	}
+
+	_, err = cfg.Seeks.Add(queue.Seek{
+		Player:      player,
+		Pool:        pool,
+		Color:       color,
+		Rating:      rating.Rating,
+		Provisional: rating.Provisional(),
+	}, time.Now())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't create the seek", err)
+		return
+	}
+
+	cfg.broadcastSeeks()
+	cfg.renderSeeks(w, r, player.ID)
+}
+
+// acceptSeekHandler starts the game of the seek right away, with the colors
+// its creator asked for.
+func (cfg *appConfig) acceptSeekHandler(w http.ResponseWriter, r *http.Request) {
+	player, guest, err := cfg.getPlayer(w, r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "couldn't get the player", err)
+		return
+	}
+
+	seek, ok := cfg.Seeks.Take(r.PathValue("id"))
+	if !ok || !cfg.Seekers.IsConnected(seek.Player.ID) {
+		cfg.broadcastSeeks()
+		cfg.lobbyError(w, errSeekGone)
+		return
+	}
+	if seek.Player.ID == player.ID {
+		cfg.Seeks.Add(seek, time.Now())
+		cfg.lobbyError(w, errOwnSeekPlay)
+		return
+	}
+	if guest && seek.Pool.Rated {
+		cfg.Seeks.Add(seek, time.Now())
+		cfg.lobbyError(w, errGuestRated)
+		return
+	}
+
+	cfg.Seeks.Remove(player.ID)
+
+	white, black := seek.Seats(queue.Seek{
+		Player: player,
+		Color:  queue.ColorRandom,
+	}, rand.IntN(2) == 0)
+
+	newGame, err := cfg.startOnlineMatch(white, black, seek.Pool.FullTime, seek.Pool.Addition, seek.Pool.Rated)
+	if err != nil {
+		cfg.Seeks.Add(seek, time.Now())
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't start the game", err)
+		return
+	}
+
+	cfg.broadcastSeeks()
+
+	msg, err := utils.TemplString(components.SeekFound(newGame))
+	if err != nil {
+		responses.LogError("couldn't render seek found", err)
+	}
+	cfg.Seekers.Send(seek.Player.ID, msg)
+
+	cfg.joinOnlineMatch(w, r, newGame, player.ID, white.ID == player.ID)
+}
+
+func (cfg *appConfig) cancelSeekHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	seek, ok := cfg.Seeks.Get(userId)
+	if ok && seek.ID == r.PathValue("id") {
+		cfg.Seeks.Remove(userId)
+		cfg.broadcastSeeks()
+	}
+
+	cfg.renderSeeks(w, r, userId)
+}
+
+func (cfg *appConfig) renderSeeks(w http.ResponseWriter, r *http.Request, userId uuid.UUID) {
+	err := components.SeekList(seekStructs(cfg.Seeks.All(), userId)).Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
+
+// broadcastSeeks sends the current list to everyone in the lobby, each with
+// their own seek marked.
+func (cfg *appConfig) broadcastSeeks() {
+	all := cfg.Seeks.All()
+
+	for _, userId := range cfg.Seekers.Subscribers() {
+		cfg.sendSeeks(all, userId)
+	}
+}
+
+func (cfg *appConfig) sendSeeks(all []queue.Seek, userId uuid.UUID) {
+	msg, err := utils.TemplString(components.SeekList(seekStructs(all, userId)))
+	if err != nil {
+		responses.LogError("couldn't render the seek list", err)
+		return
+	}
+
+	cfg.Seekers.Send(userId, msg)
+}
+
+// lobbyError keeps the seek list as it is and shows why the seek couldn't be
+// made or answered.
+func (cfg *appConfig) lobbyError(w http.ResponseWriter, lobbyErr error) {
+	w.Header().Set("HX-Reswap", "none")
+
+	msg, err := utils.TemplString(components.LobbyError(lobbyErr.Error()))
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+		return
+	}
+
+	_, err = fmt.Fprint(w, msg)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+	}
+}
+
+func timeControl(fullTime, addition int) string {
+	control := fmt.Sprintf("%v min", fullTime/60)
+	if addition != 0 {
+		control = fmt.Sprintf("%v + %v sec", control, addition)
+	}
+
+	return control
+}
+
+func seekStructs(seeks []queue.Seek, userId uuid.UUID) []components.SeekStruct {
+	list := make([]components.SeekStruct, 0, len(seeks))
+
+	for _, s := range seeks {
+		color := s.Color
+		if color == queue.ColorRandom {
+			color = "random color"
+		}
+
+		list = append(list, components.SeekStruct{
+			ID:          s.ID,
+			Name:        s.Player.Name,
+			Rating:      int(math.Round(s.Rating)),
+			Provisional: s.Provisional,
+			TimeControl: timeControl(s.Pool.FullTime, s.Pool.Addition),
+			Variant:     s.Pool.Variant,
+			Color:       color,
+			Rated:       s.Pool.Rated,
+			Own:         s.Player.ID == userId,
+		})
+	}
+
+	return list
+}
+
+// seekWsHandler keeps the lobby connected while the player looks for an
+// opponent.
+func (cfg *appConfig) seekWsHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		responses.LogError("websocket upgrade failed", err)
+		return
+	}
+
+	cfg.Seekers.Serve(userId, conn)
+}
+
+func (cfg *appConfig) seekerJoined(userId uuid.UUID) {
+	cfg.sendSeeks(cfg.Seeks.All(), userId)
+}
+
+func (cfg *appConfig) seekerLeft(userId uuid.UUID) {
+	if cfg.Seeks.Remove(userId) {
+		cfg.broadcastSeeks()
+	}
+}
+
+// joinSeekHandler moves a paired seeker onto the board of their new game.
+// White's page polls the timer.
+func (cfg *appConfig) joinSeekHandler(w http.ResponseWriter, r *http.Request) {
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	game := r.URL.Query().Get("game")
+
+	match, ok := cfg.Matches.GetMatch(game)
+	if !ok || !match.IsPlayer(userId) {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", game))
+		return
+	}
+
+	cfg.joinOnlineMatch(w, r, game, userId, match.Online.Players["white"].ID == userId)
+}
+
+func (cfg *appConfig) runMatchmaker(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		cfg.matchmake(now)
+	}
+}
+
+// matchmake starts a game for every pair of seeks the pool can make. Seeks of
+// players whose waiting connection isn't open yet go back in the pool for a
+// while, and are dropped once it's clear they won't connect.
+func (cfg *appConfig) matchmake(now time.Time) {
+	pairs := cfg.Seeks.Pairs(now)
+	if len(pairs) == 0 {
+		return
+	}
+	defer cfg.broadcastSeeks()
+
+	for _, pair := range pairs {
+		if cfg.Seekers.IsConnected(pair.First.Player.ID) && cfg.Seekers.IsConnected(pair.Second.Player.ID) {
+			cfg.startSeekGame(pair, now)
+			continue
+		}
+
+		for _, seek := range []queue.Seek{pair.First, pair.Second} {
+			if cfg.Seekers.IsConnected(seek.Player.ID) || now.Sub(seek.Since) < queue.SeekTimeout {
+				cfg.Seeks.Add(seek, now)
+			}
+		}
+	}
+}
+
+func (cfg *appConfig) startSeekGame(pair queue.Pair, now time.Time) {
+	white, black := pair.First.Seats(pair.Second, rand.IntN(2) == 0)
+
+	pool := pair.First.Pool
+
+	newGame, err := cfg.startOnlineMatch(white, black, pool.FullTime, pool.Addition, pool.Rated)
+	if err != nil {
+		responses.LogError("couldn't start a game for paired seeks", err)
+		cfg.Seeks.Add(pair.First, now)
+		cfg.Seeks.Add(pair.Second, now)
+		return
+	}
+
+	msg, err := utils.TemplString(components.SeekFound(newGame))
+	if err != nil {
+		responses.LogError("couldn't render seek found", err)
+		return
+	}
+
+	cfg.Seekers.Send(white.ID, msg)
+	cfg.Seekers.Send(black.ID, msg)
+}
@@ -0,0 +1,15 @@
+-- name: CreateChatMessage :exec
+INSERT INTO chat_messages(match_id, user_id, channel, body, created_at)
+VALUES(
+  $1,
+  $2,
+  $3,
+  $4,
+  NOW()
+);
+
+-- name: GetChatMessagesForMatch :many
+SELECT chat_messages.user_id, users.name, chat_messages.channel, chat_messages.body FROM chat_messages
+JOIN users ON users.id = chat_messages.user_id
+WHERE chat_messages.match_id = $1
+ORDER BY chat_messages.id;
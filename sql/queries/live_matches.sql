@@ -0,0 +1,19 @@
+-- name: SaveLiveMatch :exec
+INSERT INTO live_matches(game, ply, state, updated_at)
+VALUES(
+  $1,
+  $2,
+  $3,
+  NOW()
+) ON CONFLICT (game) DO UPDATE SET
+  ply = EXCLUDED.ply,
+  state = EXCLUDED.state,
+  updated_at = NOW()
+WHERE live_matches.ply <= EXCLUDED.ply;
+
+-- name: GetLiveMatches :many
+SELECT * FROM live_matches
+ORDER BY game;
+
+-- name: DeleteLiveMatch :exec
+DELETE FROM live_matches WHERE game = $1;
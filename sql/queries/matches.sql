@@ -1,11 +1,17 @@
 -- name: CreateMatch :one
-INSERT INTO matches(white, black, full_time, is_online, result, created_at)
+INSERT INTO matches(white, black, full_time, is_online, result, addition, variant, rated, start_fen, white_id, black_id, created_at)
 VALUES(
   $1,
   $2,
   $3,
   $4,
   $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $11,
   NOW()
 ) RETURNING id;
 
@@ -18,5 +24,16 @@ SELECT * FROM matches WHERE id IN (
 SELECT * FROM matches WHERE id = $1;
 
 -- name: UpdateMatchOnEnd :exec
-UPDATE matches SET ended = true, result = $1
-WHERE id = $2;
+UPDATE matches SET ended = true, result = $1, termination = $2, ended_at = NOW()
+WHERE id = $3;
+
+-- name: IsMatchEndedForUpdate :one
+SELECT ended FROM matches WHERE id = $1 FOR UPDATE;
+
+-- name: ReassignMatchPlayer :exec
+UPDATE matches SET
+  white = CASE WHEN white_id = sqlc.arg(old_id) THEN sqlc.arg(new_name) ELSE white END,
+  black = CASE WHEN black_id = sqlc.arg(old_id) THEN sqlc.arg(new_name) ELSE black END,
+  white_id = CASE WHEN white_id = sqlc.arg(old_id) THEN sqlc.arg(new_id) ELSE white_id END,
+  black_id = CASE WHEN black_id = sqlc.arg(old_id) THEN sqlc.arg(new_id) ELSE black_id END
+WHERE id = sqlc.arg(id);
@@ -7,3 +7,7 @@ VALUES(
 
 -- name: DeleteMatchUser :exec
 DELETE FROM matches_users WHERE user_id = $1 AND match_id = $2;
+
+-- name: ClaimMatchForUser :execrows
+UPDATE matches_users SET user_id = sqlc.arg(user_id)
+WHERE match_id = sqlc.arg(match_id) AND user_id = sqlc.arg(guest_id);
@@ -1,11 +1,14 @@
 -- name: CreateMove :exec
-INSERT INTO moves(board, move, white_time, black_time, match_id, created_at)
+INSERT INTO moves(board, move, white_time, black_time, match_id, time_spent, draw_offer, lag_ms, created_at)
 VALUES(
   $1,
   $2,
   $3,
   $4,
   $5,
+  $6,
+  $7,
+  $8,
   NOW()
 );
 
@@ -18,9 +21,26 @@ SELECT board, white_time, black_time FROM moves WHERE match_id = $1 AND move = $
 -- name: GetAllMovesForMatch :many
 SELECT move FROM moves WHERE match_id = $1;
 
+-- name: GetMoveTimesForMatch :many
+SELECT move, time_spent, white_time, black_time, draw_offer FROM moves WHERE match_id = $1
+ORDER BY id;
+
 -- name: UpdateBoardForMove :exec
 UPDATE moves SET board = $1 WHERE match_id = $2 AND move = $3;
 
 -- name: GetLatestMoveForMatch :one
 SELECT move, match_id FROM moves WHERE match_id = $1
-ORDER BY created_at DESC;
\ No newline at end of file
+ORDER BY created_at DESC;
+-- name: DeleteLatestMoves :exec
+DELETE FROM moves WHERE id IN (
+  SELECT id FROM moves WHERE match_id = $1
+  ORDER BY id DESC
+  LIMIT $2
+);
+
+-- name: MarkLatestMoveDrawOffer :exec
+UPDATE moves SET draw_offer = TRUE WHERE id = (
+  SELECT id FROM moves WHERE match_id = $1
+  ORDER BY id DESC
+  LIMIT 1
+);
@@ -0,0 +1,54 @@
+-- name: GetRating :one
+SELECT * FROM ratings WHERE user_id = $1 AND category = $2;
+
+-- name: EnsureRating :exec
+INSERT INTO ratings(user_id, category, rating, deviation, volatility, games, updated_at)
+VALUES(
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  0,
+  NOW()
+) ON CONFLICT (user_id, category) DO NOTHING;
+
+-- name: GetRatingForUpdate :one
+SELECT * FROM ratings WHERE user_id = $1 AND category = $2
+FOR UPDATE;
+
+-- name: UpsertRating :exec
+INSERT INTO ratings(user_id, category, rating, deviation, volatility, games, updated_at)
+VALUES(
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  1,
+  NOW()
+) ON CONFLICT (user_id, category) DO UPDATE SET
+  rating = EXCLUDED.rating,
+  deviation = EXCLUDED.deviation,
+  volatility = EXCLUDED.volatility,
+  games = ratings.games + 1,
+  updated_at = NOW();
+
+-- name: CreateRatingHistory :exec
+INSERT INTO rating_history(user_id, match_id, category, rating_before, rating_after, deviation, created_at)
+VALUES(
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  NOW()
+);
+
+-- name: GetRatingChangeForMatch :one
+SELECT * FROM rating_history WHERE match_id = $1 AND user_id = $2;
+
+-- name: GetRatingHistoryForUser :many
+SELECT * FROM rating_history WHERE user_id = $1 AND category = $2
+ORDER BY created_at;
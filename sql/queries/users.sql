@@ -13,4 +13,7 @@ VALUES(
 SELECT * FROM users WHERE email = $1;
 
 -- name: GetUserById :one
-SELECT * FROM users WHERE id = $1;
\ No newline at end of file
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetUsersByName :many
+SELECT * FROM users WHERE name = $1 LIMIT 2;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE moves ADD COLUMN time_spent INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE moves DROP COLUMN time_spent;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE matches ADD COLUMN termination TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE matches DROP COLUMN termination;
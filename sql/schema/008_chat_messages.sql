@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE chat_messages(
+  id SERIAL PRIMARY KEY,
+  match_id INT NOT NULL,
+  FOREIGN KEY (match_id)
+  REFERENCES matches(ID)
+  ON DELETE CASCADE,
+  user_id UUID NOT NULL,
+  FOREIGN KEY (user_id)
+  REFERENCES users(ID)
+  ON DELETE CASCADE,
+  channel TEXT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE chat_messages;
@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE ratings(
+  user_id UUID NOT NULL,
+  FOREIGN KEY (user_id)
+  REFERENCES users(ID)
+  ON DELETE CASCADE,
+  category TEXT NOT NULL,
+  rating DOUBLE PRECISION NOT NULL,
+  deviation DOUBLE PRECISION NOT NULL,
+  volatility DOUBLE PRECISION NOT NULL,
+  games INT DEFAULT 0 NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, category)
+);
+
+CREATE TABLE rating_history(
+  id SERIAL PRIMARY KEY,
+  user_id UUID NOT NULL,
+  FOREIGN KEY (user_id)
+  REFERENCES users(ID)
+  ON DELETE CASCADE,
+  match_id INT NOT NULL,
+  FOREIGN KEY (match_id)
+  REFERENCES matches(ID)
+  ON DELETE CASCADE,
+  category TEXT NOT NULL,
+  rating_before DOUBLE PRECISION NOT NULL,
+  rating_after DOUBLE PRECISION NOT NULL,
+  deviation DOUBLE PRECISION NOT NULL,
+  created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE rating_history;
+DROP TABLE ratings;
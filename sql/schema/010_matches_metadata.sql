@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE matches
+  ADD COLUMN addition INT NOT NULL DEFAULT 0,
+  ADD COLUMN variant TEXT NOT NULL DEFAULT 'standard',
+  ADD COLUMN rated BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN start_fen TEXT NOT NULL DEFAULT 'rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1',
+  ADD COLUMN ended_at TIMESTAMP,
+  ADD COLUMN white_id UUID,
+  ADD COLUMN black_id UUID;
+
+-- +goose Down
+ALTER TABLE matches
+  DROP COLUMN black_id,
+  DROP COLUMN white_id,
+  DROP COLUMN ended_at,
+  DROP COLUMN start_fen,
+  DROP COLUMN rated,
+  DROP COLUMN variant,
+  DROP COLUMN addition;
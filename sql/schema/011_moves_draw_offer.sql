@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE moves ADD COLUMN draw_offer BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE moves DROP COLUMN draw_offer;
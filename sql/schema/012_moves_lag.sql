@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE moves ADD COLUMN lag_ms INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE moves DROP COLUMN lag_ms;
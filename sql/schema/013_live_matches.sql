@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE live_matches(
+  game TEXT PRIMARY KEY,
+  ply INT NOT NULL,
+  state JSONB NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE live_matches;
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/protocol"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+// takebackHandler undoes the last move of a local game straight away, while
+// in an online game it asks the opponent to accept the takeback.
+func (cfg *appConfig) takebackHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	match, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+
+	if !match.IsOnline {
+		cfg.undoLocalMove(w, r, currentGame)
+		return
+	}
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	waitMsg, err := utils.TemplString(components.WaitForTakebackModal())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+		return
+	}
+	offeredMsg, err := utils.TemplString(components.TakebackOfferedModal())
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+		return
+	}
+
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		err = match.RequestTakeback(userId)
+		if err != nil {
+			return
+		}
+
+		match.Online.Hub.Send(userId, waitMsg)
+		if opponent, found := match.Opponent(userId); found {
+			match.Online.Hub.Send(opponent.ID, offeredMsg)
+		}
+	})
+
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+	}
+}
+
+func (cfg *appConfig) undoLocalMove(w http.ResponseWriter, r *http.Request, currentGame string) {
+	var msg string
+	var matchId int32
+	var err error
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Result != "" || !match.TakeBack(1) {
+			err = matches.ErrNoTakeback
+			return
+		}
+
+		matchId = match.MatchId
+		msg, err = takenBackMessage(match, match.CoordinateMultiplier)
+	})
+
+	if errors.Is(err, matches.ErrNoTakeback) {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "error converting component to string", err)
+		return
+	}
+
+	// Resumed games don't store their moves, so there is nothing to delete.
+	if !strings.HasPrefix(currentGame, "database:") {
+		err = cfg.deleteTakenBackMoves(r.Context(), matchId, 1)
+		if err != nil {
+			responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't delete the moves", err)
+			return
+		}
+	}
+	cfg.rewindCheckpoint(r.Context(), currentGame)
+
+	_, err = fmt.Fprint(w, msg)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+	}
+}
+
+func (cfg *appConfig) acceptTakebackHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	var plies int
+	var matchId int32
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		plies, err = match.AcceptTakeback(userId)
+		if err != nil {
+			return
+		}
+		matchId = match.MatchId
+
+		for _, player := range match.Online.Players {
+			msg, err := takenBackMessage(match, player.Multiplier)
+			if err != nil {
+				responses.LogError("couldn't render taken back board", err)
+				continue
+			}
+
+			match.Online.Hub.Send(player.ID, msg+`
+				<div id="rec" hx-swap-oob="outerHTML"></div>
+				<div id="wait" hx-swap-oob="outerHTML"></div>
+			`)
+		}
+
+		if match.Online.Spectators != nil {
+			for spectatorId, multiplier := range match.Online.SpectatorMultipliers {
+				msg, err := watchGameMessage(match, multiplier)
+				if err != nil {
+					responses.LogError("couldn't render watched game", err)
+					continue
+				}
+
+				match.Online.Spectators.Send(spectatorId, msg)
+			}
+		}
+
+		match.Publish(protocol.NewTakeback(len(match.UciMoves), protocol.Clock{White: match.WhiteTimer, Black: match.BlackTimer}))
+	})
+
+	if !ok {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("match %v doesn't exist", currentGame))
+		return
+	}
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	cfg.rewindCheckpoint(r.Context(), currentGame)
+
+	err = cfg.deleteTakenBackMoves(r.Context(), matchId, plies)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't delete the moves", err)
+	}
+}
+
+func (cfg *appConfig) declineTakebackHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame, err := cfg.currentGame(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", err)
+		return
+	}
+
+	userId, err := cfg.getUserId(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusUnauthorized, "unauthorized user", err)
+		return
+	}
+
+	var requester uuid.UUID
+	cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		requester, err = match.DeclineTakeback(userId)
+		if err != nil {
+			return
+		}
+
+		match.Online.Hub.Send(requester, `<div id="wait" hx-swap-oob="outerHTML"></div>`)
+	})
+
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusConflict, err.Error(), err)
+		return
+	}
+
+	_, err = fmt.Fprint(w, `<div id="rec" hx-swap-oob="outerHTML"></div>`)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't write to page", err)
+	}
+}
+
+// takenBackMessage puts the board, clocks, captured pieces and moves list
+// back the way they were before the taken back moves.
+func takenBackMessage(match *matches.Match, multiplier int) (string, error) {
+	snapshot := match.SpectatorSnapshot(multiplier)
+
+	return utils.TemplString(components.TakenBack(
+		snapshot.Board,
+		snapshot.Pieces,
+		multiplier,
+		utils.FormatTime(snapshot.WhiteTimer),
+		utils.FormatTime(snapshot.BlackTimer),
+		snapshot.TakenPiecesWhite,
+		snapshot.TakenPiecesBlack,
+		snapshot.AllMoves,
+		snapshot.MoveState(),
+	))
+}
+
+// deleteTakenBackMoves drops the stored moves that were taken back, so the
+// match history only has the moves that stand.
+func (cfg *appConfig) deleteTakenBackMoves(ctx context.Context, matchId int32, plies int) error {
+	if matchId == 0 {
+		return nil
+	}
+
+	return cfg.database.DeleteLatestMoves(ctx, database.DeleteLatestMovesParams{
+		MatchID: matchId,
+		Limit:   int32(plies),
+	})
+}
@@ -1,16 +1,30 @@
 package main
 
 import (
+	"database/sql"
+	"time"
+
+	"github.com/NikolaTosic-sudo/chess-live/internal/challenges"
+	"github.com/NikolaTosic-sudo/chess-live/internal/chat"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
 	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/queue"
 	"github.com/google/uuid"
 )
 
 type appConfig struct {
-	database *database.Queries
-	secret   string
-	users    map[uuid.UUID]User
-	Matches  matches.Matches
+	db               *sql.DB
+	database         *database.Queries
+	secret           string
+	users            map[uuid.UUID]User
+	Matches          *matches.Matches
+	Rematches        *matches.Rematches
+	Challenges       *challenges.Store
+	Seeks            *queue.SeekPool
+	Seekers          *hub.Hub
+	chatFilter       chat.Filter
+	firstMoveTimeout time.Duration
 }
 
 type User struct {
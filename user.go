@@ -5,13 +5,29 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
 	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
 	"github.com/google/uuid"
 )
 
+// getUserId identifies whoever is playing, a logged in user or a guest.
 func (cfg *appConfig) getUserId(r *http.Request) (uuid.UUID, error) {
+	userId, err := cfg.getAccountId(r)
+	if err == nil {
+		return userId, nil
+	}
+
+	guestId, guestErr := cfg.getGuestId(r)
+	if guestErr != nil {
+		return userId, err
+	}
+
+	return guestId, nil
+}
+
+func (cfg *appConfig) getAccountId(r *http.Request) (uuid.UUID, error) {
 	userId, err := uuid.NewUUID()
 
 	if err != nil {
@@ -40,7 +56,7 @@ func (cfg *appConfig) getUserId(r *http.Request) (uuid.UUID, error) {
 func (cfg *appConfig) getUser(r *http.Request) (database.User, error) {
 	user := database.User{}
 
-	userId, err := cfg.getUserId(r)
+	userId, err := cfg.getAccountId(r)
 
 	if err != nil {
 		return user, err
@@ -56,6 +72,59 @@ func (cfg *appConfig) getUser(r *http.Request) (database.User, error) {
 	return user, nil
 }
 
+func (cfg *appConfig) getGuestId(r *http.Request) (uuid.UUID, error) {
+	guestC, err := r.Cookie("guest_token")
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return auth.ValidateGuestJWT(guestC.Value, cfg.secret)
+}
+
+// getPlayer describes the user for an online game. Visitors without an
+// account play as a guest, under a name made from their guest id, which is
+// created on their first game.
+func (cfg *appConfig) getPlayer(w http.ResponseWriter, r *http.Request) (components.OnlinePlayerStruct, bool, error) {
+	multiplier, err := cfg.getMultiplier(r)
+	if err != nil {
+		return components.OnlinePlayerStruct{}, false, err
+	}
+
+	player := components.OnlinePlayerStruct{
+		Image:      "/assets/images/user-icon.png",
+		Multiplier: multiplier,
+	}
+
+	if user, err := cfg.getUser(r); err == nil {
+		player.ID = user.ID
+		player.Name = user.Name
+		return player, false, nil
+	}
+
+	guestId, err := cfg.getGuestId(r)
+	if err != nil {
+		guestId = uuid.New()
+
+		token, err := auth.MakeGuestJWT(guestId, cfg.secret)
+		if err != nil {
+			return components.OnlinePlayerStruct{}, false, err
+		}
+
+		guestC := cfg.makeCookieMaxAge("guest_token", token, "/", int(auth.GuestTokenDuration.Seconds()))
+		http.SetCookie(w, &guestC)
+	}
+
+	player.ID = guestId
+	player.Name = guestName(guestId)
+	player.Guest = true
+
+	return player, true, nil
+}
+
+func guestName(guestId uuid.UUID) string {
+	return "Guest-" + guestId.String()[:6]
+}
+
 func (cfg *appConfig) removeCookiePath(name, path string) http.Cookie {
 	cookie := http.Cookie{
 		Name:     name,
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/NikolaTosic-sudo/chess-live/internal/auth"
 	"github.com/NikolaTosic-sudo/chess-live/internal/database"
 	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/google/uuid"
 )
 
 func (cfg *appConfig) loginOpenHandler(w http.ResponseWriter, r *http.Request) {
@@ -30,7 +32,16 @@ func (cfg *appConfig) closeModalHandler(w http.ResponseWriter, r *http.Request)
 
 func (cfg *appConfig) signupModalHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
-	err := components.Signup().Render(r.Context(), w)
+	err := components.Signup("").Render(r.Context(), w)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
+	}
+}
+
+// claimModalHandler offers a guest to sign up and take the finished game
+// with them.
+func (cfg *appConfig) claimModalHandler(w http.ResponseWriter, r *http.Request) {
+	err := layout.ClaimModal(r.URL.Query().Get("match")).Render(r.Context(), w)
 	if err != nil {
 		responses.RespondWithAnError(w, http.StatusInternalServerError, "couldn't render template", err)
 	}
@@ -106,13 +117,8 @@ func (cfg *appConfig) signupHandler(w http.ResponseWriter, r *http.Request) {
 
 	refreshC := cfg.makeCookie("refresh_token", refreshString, "/api/refresh")
 
-	cGC := cfg.removeCookie("current_game")
-	sGC := cfg.removeCookie("saved_game")
-
 	http.SetCookie(w, &c)
 	http.SetCookie(w, &refreshC)
-	http.SetCookie(w, &cGC)
-	http.SetCookie(w, &sGC)
 
 	cfg.users[user.ID] = User{
 		Id:    user.ID,
@@ -120,9 +126,61 @@ func (cfg *appConfig) signupHandler(w http.ResponseWriter, r *http.Request) {
 		Email: user.Email,
 	}
 
+	if claim := r.FormValue("claim"); claim != "" {
+		err = cfg.claimGuestMatch(r, claim, user.ID, user.Name)
+		if err != nil {
+			responses.LogError("couldn't claim the guest game", err)
+		}
+	}
+
 	w.Header().Add("Hx-Redirect", "/private")
 }
 
+// claimGuestMatch moves a game the guest played over to their new account.
+func (cfg *appConfig) claimGuestMatch(r *http.Request, claim string, userId uuid.UUID, name string) error {
+	guestId, err := cfg.getGuestId(r)
+	if err != nil {
+		return err
+	}
+
+	matchId, err := strconv.Atoi(claim)
+	if err != nil {
+		return err
+	}
+
+	tx, err := cfg.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := cfg.database.WithTx(tx)
+
+	claimed, err := q.ClaimMatchForUser(r.Context(), database.ClaimMatchForUserParams{
+		UserID:  userId,
+		MatchID: int32(matchId),
+		GuestID: guestId,
+	})
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return fmt.Errorf("guest %v didn't play match %v", guestId, matchId)
+	}
+
+	err = q.ReassignMatchPlayer(r.Context(), database.ReassignMatchPlayerParams{
+		OldID:   uuid.NullUUID{UUID: guestId, Valid: true},
+		NewName: name,
+		NewID:   uuid.NullUUID{UUID: userId, Valid: true},
+		ID:      int32(matchId),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (cfg *appConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 	email := r.FormValue("email")
 	password := r.FormValue("password")
@@ -188,13 +246,8 @@ func (cfg *appConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
 	c := cfg.makeCookieMaxAge("access_token", token, "/", 3600)
 	refreshC := cfg.makeCookie("refresh_token", refreshString, "/api/refresh")
 
-	cGC := cfg.removeCookie("current_game")
-	sGC := cfg.removeCookie("saved_game")
-
 	http.SetCookie(w, &c)
 	http.SetCookie(w, &refreshC)
-	http.SetCookie(w, &cGC)
-	http.SetCookie(w, &sGC)
 
 	cfg.users[user.ID] = User{
 		Id:    user.ID,
@@ -225,15 +278,11 @@ func (cfg *appConfig) logoutHandler(w http.ResponseWriter, r *http.Request) {
 
 	delete(cfg.users, userId)
 
-	cGC := cfg.removeCookie("current_game")
 	accC := cfg.removeCookie("access_token")
-	sGC := cfg.removeCookie("saved_game")
 	refreshC := cfg.removeCookiePath("refresh_token", "/api/refresh")
 
 	http.SetCookie(w, &accC)
 	http.SetCookie(w, &refreshC)
-	http.SetCookie(w, &cGC)
-	http.SetCookie(w, &sGC)
 
 	w.Header().Add("Hx-Redirect", "/")
 }
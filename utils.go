@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -134,7 +137,7 @@ func (cfg *appConfig) middleWareCheckForUserPrivate(next func(http.ResponseWrite
 }
 
 func (cfg *appConfig) isUserLoggedIn(r *http.Request) (uuid.UUID, error) {
-	userId, err := cfg.getUserId(r)
+	userId, err := cfg.getAccountId(r)
 
 	if err != nil {
 		return uuid.Nil, err
@@ -152,40 +155,60 @@ func (cfg *appConfig) isUserLoggedIn(r *http.Request) (uuid.UUID, error) {
 	return userId, nil
 }
 
-func (cfg *appConfig) showMoves(match matches.Match, squareName, pieceName string, w http.ResponseWriter, r *http.Request) error {
-	c, err := r.Cookie("current_game")
+// currentGame is the key of the game on the page the request came from. Every
+// game has a page of its own at /game/{id} and every replay one at
+// /matches/{id}?replay={replayId}, htmx names the page in the HX-Current-URL
+// header.
+func (cfg *appConfig) currentGame(r *http.Request) (string, error) {
+	page, err := url.Parse(r.Header.Get("HX-Current-URL"))
 	if err != nil {
-		return err
+		return "", err
 	}
-	if c.Value != "" {
-		if strings.Split(c.Value, ":")[0] == "database" {
-			return err
+
+	if id, ok := strings.CutPrefix(page.Path, "/game/"); ok && id != "" {
+		return matches.GameKey(id), nil
+	}
+	if id, ok := strings.CutPrefix(page.Path, "/matches/"); ok && id != "" {
+		if replay := page.Query().Get("replay"); replay != "" {
+			return matches.ReplayKey(id, replay), nil
 		}
 	}
 
-	boardState := make(map[string]string, 0)
-	for k, v := range match.Pieces {
-		boardState[k] = v.Tile
-	}
+	return "", fmt.Errorf("%w on %v", errGameNotFound, page.Path)
+}
 
-	jsonBoard, err := json.Marshal(boardState)
+// storesMoves reports whether the moves of the game are kept in the match
+// history. Only games with a stored match are, replays never are.
+func storesMoves(currentGame string, match *matches.Match) bool {
+	return match.MatchId != 0 && !strings.HasPrefix(currentGame, "database:")
+}
 
-	if err != nil {
-		return err
+func (cfg *appConfig) showMoves(ctx context.Context, w io.Writer, match *matches.Match, currentGame, squareName, pieceName string) error {
+	if strings.HasPrefix(currentGame, "database:") {
+		return nil
 	}
 
-	userId, err := cfg.isUserLoggedIn(r)
-	if err != nil && !strings.Contains(err.Error(), "named cookie not present") {
-		return err
-	}
+	if storesMoves(currentGame, match) {
+		boardState := make(map[string]string, 0)
+		for k, v := range match.Pieces {
+			boardState[k] = v.Tile
+		}
+
+		jsonBoard, err := json.Marshal(boardState)
+
+		if err != nil {
+			return err
+		}
 
-	if userId != uuid.Nil {
-		err = cfg.database.CreateMove(r.Context(), database.CreateMoveParams{
+		err = cfg.database.CreateMove(ctx, database.CreateMoveParams{
 			Board:     jsonBoard,
 			Move:      fmt.Sprintf("%v:%v", pieceName, squareName),
 			WhiteTime: int32(match.WhiteTimer),
 			BlackTime: int32(match.BlackTimer),
+			TimeSpent: int32(match.TimeSpentOnTurn()),
 			MatchID:   match.MatchId,
+			DrawOffer: match.DrawOfferedOnLastMove(),
+			LagMs:     int32(match.MoverLag().Milliseconds()),
 		})
 
 		if err != nil {
@@ -207,8 +230,12 @@ func (cfg *appConfig) showMoves(match matches.Match, squareName, pieceName strin
 		)
 	}
 
-	cfg.Matches.SetMatch(c.Value, match)
+	err := match.SendMessage(w, message, [2][]int{})
+	if err != nil {
+		return err
+	}
 
+	message = fmt.Sprintf(responses.GetMoveStateMessage(), len(match.AllMoves), "")
 	err = match.SendMessage(w, message, [2][]int{})
 
 	return err
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NikolaTosic-sudo/chess-live/containers/components"
+	layout "github.com/NikolaTosic-sudo/chess-live/containers/layouts"
+	"github.com/NikolaTosic-sudo/chess-live/internal/hub"
+	"github.com/NikolaTosic-sudo/chess-live/internal/matches"
+	"github.com/NikolaTosic-sudo/chess-live/internal/responses"
+	"github.com/NikolaTosic-sudo/chess-live/internal/utils"
+	"github.com/google/uuid"
+)
+
+func (cfg *appConfig) watchHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame := matches.WatchGameKey(r.PathValue("id"))
+
+	multiplier, err := cfg.getMultiplier(r)
+	if err != nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Please try again")
+		return
+	}
+
+	match, ok := cfg.Matches.GetMatch(currentGame)
+	if !ok || match.Online.Spectators == nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	snapshot := match.SpectatorSnapshot(multiplier)
+
+	err = layout.WatchOnline(
+		matches.WatchPath(currentGame),
+		snapshot.SpectatorCount(),
+		snapshot.Board,
+		snapshot.Pieces,
+		multiplier,
+		snapshot.Online.Players["white"],
+		snapshot.Online.Players["black"],
+		snapshot.TakenPiecesWhite,
+		snapshot.TakenPiecesBlack,
+		snapshot.AllMoves,
+	).Render(r.Context(), w)
+
+	if err != nil {
+		responses.RespondWithAnErrorPage(w, r, http.StatusInternalServerError, "Couldn't render template")
+	}
+}
+
+func (cfg *appConfig) watchWsHandler(w http.ResponseWriter, r *http.Request) {
+	currentGame := matches.WatchGameKey(r.PathValue("id"))
+
+	multiplier, err := cfg.getMultiplier(r)
+	if err != nil {
+		responses.RespondWithAnError(w, http.StatusBadRequest, "invalid multiplier", err)
+		return
+	}
+
+	// Logged in spectators are known by their user id, so they can chat.
+	spectatorId := uuid.New()
+	if userId, err := cfg.getUserId(r); err == nil {
+		spectatorId = userId
+	}
+
+	var spectators *hub.Hub
+	ok := cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Online.Spectators == nil {
+			return
+		}
+		spectators = match.Online.Spectators
+		match.AddSpectator(spectatorId, multiplier)
+	})
+
+	if !ok || spectators == nil {
+		responses.RespondWithAnError(w, http.StatusNotFound, "game not found", fmt.Errorf("online match %v doesn't exist", currentGame))
+		return
+	}
+
+	defer cfg.Matches.Do(currentGame, func(match *matches.Match) {
+		if match.Online.Spectators.IsConnected(spectatorId) {
+			return
+		}
+		match.RemoveSpectator(spectatorId)
+		match.BroadcastSpectatorCount()
+	})
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		responses.LogError("websocket upgrade failed", err)
+		return
+	}
+
+	spectators.Serve(spectatorId, conn)
+}
+
+// spectatorJoined sends a new spectator the whole game, since anything played
+// between loading the watch page and connecting would otherwise be missing.
+func (cfg *appConfig) spectatorJoined(currentGame string) func(uuid.UUID) {
+	return func(spectatorId uuid.UUID) {
+		cfg.Matches.Do(currentGame, func(match *matches.Match) {
+			msg, err := watchGameMessage(match, match.Online.SpectatorMultipliers[spectatorId])
+			if err != nil {
+				responses.LogError("couldn't render watched game", err)
+				return
+			}
+
+			match.Online.Spectators.Send(spectatorId, msg)
+			match.BroadcastSpectatorCount()
+		})
+	}
+}
+
+// watchGameMessage renders the whole game the way a spectator sees it.
+func watchGameMessage(match *matches.Match, multiplier int) (string, error) {
+	snapshot := match.SpectatorSnapshot(multiplier)
+
+	return utils.TemplString(components.WatchGame(
+		snapshot.Board,
+		snapshot.Pieces,
+		multiplier,
+		snapshot.Online.Players["white"],
+		snapshot.Online.Players["black"],
+		snapshot.TakenPiecesWhite,
+		snapshot.TakenPiecesBlack,
+		snapshot.AllMoves,
+	))
+}